@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pro200/go-utils"
+)
+
+// http.DetectContentType 가 판별에 사용하는 최대 크기
+const sniffSize = 512
+
+// ContentTypeMismatch 는 저장된 Content-Type 과 실제 내용이 다른 객체
+type ContentTypeMismatch struct {
+	Key      string `json:"key"`
+	Stored   string `json:"stored"`
+	Detected string `json:"detected"`
+	Fixed    bool   `json:"fixed"`
+	Err      error  `json:"-"` // 샘플 읽기 또는 수정 실패
+}
+
+// AuditContentTypes 는 prefix 아래 객체의 앞 512 byte 를 읽어 저장된 Content-Type 과 비교하고 다른 객체 목록을 반환한다.
+// fix 가 true 이면 서버 측 복사로 Content-Type 을 판별한 값으로 바꾼다.
+// 내용으로 판별할 수 없는 객체(텍스트, 알 수 없는 바이너리)는 key 확장자를 기준으로 하고, 그래도 모르면 건너뛴다.
+// 개별 객체 실패는 Err 에 담고 계속 진행하며, 목록 조회 실패만 오류로 반환한다.
+func (s *Storage) AuditContentTypes(bucket, prefix string, fix ...bool) ([]ContentTypeMismatch, error) {
+	var mismatches []ContentTypeMismatch
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		if obj.Size == 0 {
+			return nil
+		}
+		if _, err := s.WaitBlackout(); err != nil {
+			return err
+		}
+
+		stored, sample, err := s.sniff(bucket, obj.Key)
+		if err != nil {
+			mismatches = append(mismatches, ContentTypeMismatch{Key: obj.Key, Err: err})
+			return nil
+		}
+
+		detected := expectedContentType(obj.Key, sample)
+		if detected == "" || sameMediaType(stored, detected) {
+			return nil
+		}
+
+		mismatch := ContentTypeMismatch{Key: obj.Key, Stored: stored, Detected: detected}
+		if len(fix) > 0 && fix[0] {
+			mismatch.Err = s.replaceMetadata(bucket, obj.Key, func(headers *objectHeaders) {
+				headers.ContentType = detected
+			})
+			// 그 사이 이미 수정됨
+			if mismatch.Err == errSkipped {
+				mismatch.Err = nil
+			}
+			mismatch.Fixed = mismatch.Err == nil
+		}
+		mismatches = append(mismatches, mismatch)
+		return nil
+	})
+
+	return mismatches, err
+}
+
+// sniff 는 저장된 Content-Type 과 객체 앞부분을 반환한다.
+func (s *Storage) sniff(bucket, key string) (string, []byte, error) {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	output, err := s.client.GetObject(s.requestContext(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", sniffSize-1)),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	defer output.Body.Close()
+
+	sample, err := io.ReadAll(io.LimitReader(output.Body, sniffSize))
+	if err != nil {
+		return "", nil, err
+	}
+	return aws.ToString(output.ContentType), sample, nil
+}
+
+// expectedContentType 내용과 key 확장자로 판별한 Content-Type, 판별할 수 없으면 ""
+func expectedContentType(key string, sample []byte) string {
+	detected := http.DetectContentType(sample)
+	byExt := utils.ContentType(key)
+	if byExt == "application/octet-stream" {
+		byExt = ""
+	}
+
+	detectedType, _, _ := mime.ParseMediaType(detected)
+	switch detectedType {
+	// 내용만으로는 구체적인 형식을 알 수 없음 (json, css, svg, docx 등)
+	case "application/octet-stream", "text/plain", "text/xml", "application/zip", "application/x-gzip":
+		return byExt
+	}
+
+	// 영상/음성 컨테이너는 같은 계열이면 확장자가 더 구체적 (예: video/mp4 ↔ video/quicktime)
+	switch family := mediaFamily(detectedType); family {
+	case "video", "audio":
+		if mediaFamily(byExt) == family {
+			return byExt
+		}
+	}
+	return detected
+}
+
+func sameMediaType(a, b string) bool {
+	a, _, _ = mime.ParseMediaType(a)
+	b, _, _ = mime.ParseMediaType(b)
+	return a != "" && a == b
+}
+
+func mediaFamily(contentType string) string {
+	family, _, _ := strings.Cut(contentType, "/")
+	return family
+}
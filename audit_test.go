@@ -0,0 +1,30 @@
+package storage
+
+import "testing"
+
+func TestExpectedContentType(t *testing.T) {
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+	tests := []struct {
+		key    string
+		sample []byte
+		want   string
+	}{
+		{"a/photo.jpg", png, "image/png"},
+		{"a/photo.png", png, "image/png"},
+		{"a/movie.mov", []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), "video/quicktime"},
+		{"a/data.json", []byte(`{"a": 1}`), "application/json"},
+		{"a/icon.svg", []byte(`<?xml version="1.0"?><svg></svg>`), "image/svg+xml"},
+		{"a/index.html", []byte("<!DOCTYPE html><html></html>"), "text/html; charset=utf-8"},
+		{"a/unknown", []byte{0x00, 0x01, 0x02}, ""},
+	}
+
+	for _, test := range tests {
+		if got := expectedContentType(test.key, test.sample); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.key, got, test.want)
+		}
+	}
+
+	if !sameMediaType("text/html; charset=utf-8", "TEXT/HTML") || sameMediaType("", "") {
+		t.Error("sameMediaType")
+	}
+}
@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidReport = errors.New("invalid integrity report signature")
+
+const (
+	baselineSamples    = 4        // 객체당 sample 구간 수
+	baselineSampleSize = 64 << 10 // sample 구간 크기
+)
+
+// Baseline 은 prefix 아래 객체의 checksum 기준값. JSON 으로 내보내 두었다가 VerifyAgainstBaseline 에 넘긴다.
+type Baseline struct {
+	Bucket  string                    `json:"bucket"`
+	Prefix  string                    `json:"prefix"`
+	Created time.Time                 `json:"created"`
+	Objects map[string]BaselineObject `json:"objects"` // key: prefix 기준 상대 경로
+}
+
+type BaselineObject struct {
+	Size    int64       `json:"size"`
+	SHA256  string      `json:"sha256"`
+	Samples []RangeHash `json:"samples,omitempty"` // sample 검증용 구간 해시
+}
+
+// RangeHash 는 객체 일부 구간의 SHA-256
+type RangeHash struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// IntegrityReport 는 VerifyAgainstBaseline 결과. Signature 를 뺀 JSON 에 대한 ed25519 서명을 포함한다.
+type IntegrityReport struct {
+	Bucket          string            `json:"bucket"`
+	Prefix          string            `json:"prefix"`
+	BaselineCreated time.Time         `json:"baseline_created"`
+	VerifiedAt      time.Time         `json:"verified_at"`
+	Full            bool              `json:"full"` // false 이면 sample 구간만 확인
+	Checked         int               `json:"checked"`
+	Missing         []string          `json:"missing,omitempty"`
+	Mismatched      []string          `json:"mismatched,omitempty"`
+	Unexpected      []string          `json:"unexpected,omitempty"` // baseline 에 없는 객체
+	Failed          map[string]string `json:"failed,omitempty"`     // 확인 중 오류
+	Signature       []byte            `json:"signature,omitempty"`
+}
+
+// OK 는 누락, 불일치, 오류가 없으면 true. Unexpected 는 판단에 넣지 않는다.
+func (r *IntegrityReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0 && len(r.Failed) == 0
+}
+
+// Verify 는 report 의 서명을 확인한다.
+func (r *IntegrityReport) Verify(publicKey ed25519.PublicKey) error {
+	payload, err := r.payload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, r.Signature) {
+		return ErrInvalidReport
+	}
+	return nil
+}
+
+func (r *IntegrityReport) payload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
+
+// ExportBaseline 은 prefix 아래 모든 객체를 내려받아 SHA-256 과 sample 구간 해시를 기록한다.
+func (s *Storage) ExportBaseline(bucket, prefix string) (*Baseline, error) {
+	baseline := &Baseline{
+		Bucket:  bucket,
+		Prefix:  prefix,
+		Created: time.Now().UTC(),
+		Objects: map[string]BaselineObject{},
+	}
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		object, err := s.baselineObject(bucket, obj.Key, obj.Size)
+		if err != nil {
+			return fmt.Errorf("%s: %w", obj.Key, err)
+		}
+		baseline.Objects[strings.TrimPrefix(obj.Key, prefix)] = object
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+func (s *Storage) baselineObject(bucket, key string, size int64) (BaselineObject, error) {
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return BaselineObject{}, err
+	}
+	defer output.Body.Close()
+
+	samples := newRangeHasher(sampleRanges(size))
+	full := sha256.New()
+	n, err := io.Copy(io.MultiWriter(full, samples), output.Body)
+	if err != nil {
+		return BaselineObject{}, err
+	}
+
+	return BaselineObject{Size: n, SHA256: hex.EncodeToString(full.Sum(nil)), Samples: samples.sums()}, nil
+}
+
+// VerifyAgainstBaseline 은 현재 객체를 baseline 과 비교하고 privateKey 로 서명한 보고서를 반환한다.
+// 기본은 객체마다 sample 구간만 range 요청으로 확인하고, full 이 true 이면 전체를 내려받아 SHA-256 을 비교한다.
+// 개별 객체 실패는 보고서에 기록하며, 목록 조회 실패만 오류로 반환한다.
+func (s *Storage) VerifyAgainstBaseline(baseline *Baseline, privateKey ed25519.PrivateKey, full ...bool) (*IntegrityReport, error) {
+	report := &IntegrityReport{
+		Bucket:          baseline.Bucket,
+		Prefix:          baseline.Prefix,
+		BaselineCreated: baseline.Created,
+		Full:            len(full) > 0 && full[0],
+		Failed:          map[string]string{},
+	}
+
+	current := map[string]int64{}
+	err := s.Walk(baseline.Bucket, baseline.Prefix, func(obj ObjectInfo) error {
+		current[strings.TrimPrefix(obj.Key, baseline.Prefix)] = obj.Size
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(baseline.Objects)) {
+		expected := baseline.Objects[name]
+		size, ok := current[name]
+		if !ok {
+			report.Missing = append(report.Missing, name)
+			continue
+		}
+		delete(current, name)
+		report.Checked++
+
+		same, err := s.matchesBaseline(baseline.Bucket, baseline.Prefix+name, size, expected, report.Full)
+		switch {
+		case err != nil:
+			report.Failed[name] = err.Error()
+		case !same:
+			report.Mismatched = append(report.Mismatched, name)
+		}
+	}
+	report.Unexpected = slices.Sorted(maps.Keys(current))
+	report.VerifiedAt = time.Now().UTC()
+
+	payload, err := report.payload()
+	if err != nil {
+		return nil, err
+	}
+	report.Signature = ed25519.Sign(privateKey, payload)
+	return report, nil
+}
+
+func (s *Storage) matchesBaseline(bucket, key string, size int64, expected BaselineObject, full bool) (bool, error) {
+	if size != expected.Size {
+		return false, nil
+	}
+
+	if full {
+		actual, err := s.hashObject(bucket, key)
+		if err != nil {
+			return false, err
+		}
+		return actual.SHA256 == expected.SHA256, nil
+	}
+
+	for _, sample := range expected.Samples {
+		output, err := s.getRange(bucket, key, sample.Offset, sample.Length)
+		if err != nil {
+			return false, err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, io.LimitReader(output.Body, sample.Length))
+		output.Body.Close()
+		if err != nil {
+			return false, err
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != sample.SHA256 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// sampleRanges 처음과 끝을 포함해 고르게 나눈 구간, 작은 객체는 전체 한 구간
+func sampleRanges(size int64) []RangeHash {
+	if size <= 0 {
+		return nil
+	}
+	if size <= baselineSamples*baselineSampleSize {
+		return []RangeHash{{Offset: 0, Length: size}}
+	}
+
+	ranges := make([]RangeHash, baselineSamples)
+	step := (size - baselineSampleSize) / (baselineSamples - 1)
+	for i := range ranges {
+		ranges[i] = RangeHash{Offset: int64(i) * step, Length: baselineSampleSize}
+	}
+	ranges[len(ranges)-1].Offset = size - baselineSampleSize
+	return ranges
+}
+
+// rangeHasher 는 순서대로 쓰이는 데이터에서 지정한 구간의 해시를 계산한다.
+type rangeHasher struct {
+	ranges []RangeHash
+	hashes []hash.Hash
+	offset int64
+}
+
+func newRangeHasher(ranges []RangeHash) *rangeHasher {
+	h := &rangeHasher{ranges: ranges, hashes: make([]hash.Hash, len(ranges))}
+	for i := range h.hashes {
+		h.hashes[i] = sha256.New()
+	}
+	return h
+}
+
+func (h *rangeHasher) Write(b []byte) (int, error) {
+	start, end := h.offset, h.offset+int64(len(b))
+	for i, r := range h.ranges {
+		from, to := max(start, r.Offset), min(end, r.Offset+r.Length)
+		if from < to {
+			h.hashes[i].Write(b[from-start : to-start])
+		}
+	}
+	h.offset = end
+	return len(b), nil
+}
+
+func (h *rangeHasher) sums() []RangeHash {
+	sums := slices.Clone(h.ranges)
+	for i := range sums {
+		sums[i].SHA256 = hex.EncodeToString(h.hashes[i].Sum(nil))
+	}
+	return sums
+}
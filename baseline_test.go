@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRangeHasher(t *testing.T) {
+	if ranges := sampleRanges(100); len(ranges) != 1 || ranges[0].Length != 100 {
+		t.Error(ranges)
+	}
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<15) // 512 KiB
+	ranges := sampleRanges(int64(len(data)))
+	last := ranges[len(ranges)-1]
+	if len(ranges) != baselineSamples || ranges[0].Offset != 0 || last.Offset+last.Length != int64(len(data)) {
+		t.Fatal(ranges)
+	}
+
+	// 경계와 맞지 않는 크기로 나눠 씀
+	h := newRangeHasher(ranges)
+	for chunk := range slices.Chunk(data, 1000) {
+		h.Write(chunk)
+	}
+	for _, sample := range h.sums() {
+		sum := sha256.Sum256(data[sample.Offset : sample.Offset+sample.Length])
+		if sample.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Error("구간 해시가 다름:", sample.Offset)
+		}
+	}
+}
+
+func TestIntegrityReportSignature(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+
+	report := &IntegrityReport{Bucket: "bucket", Checked: 2, Mismatched: []string{"a.bin"}}
+	payload, _ := report.payload()
+	report.Signature = ed25519.Sign(privateKey, payload)
+	if err := report.Verify(publicKey); err != nil || report.OK() {
+		t.Error(err, report.OK())
+	}
+
+	report.Mismatched = nil
+	if err := report.Verify(publicKey); !errors.Is(err, ErrInvalidReport) {
+		t.Error("변경된 보고서가 통과함:", err)
+	}
+}
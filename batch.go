@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// DeleteObjects 한 번에 삭제할 수 있는 최대 key 수
+const maxDeleteKeys = 1000
+
+// DeleteMany 는 DeleteObjects API 로 keys 를 1000개씩 묶어 삭제한다.
+// 삭제하지 못한 key 는 failed 에 key 별 오류로 담기고, 요청 자체가 실패하면 err 를 반환한다.
+// 없는 key 는 S3 와 마찬가지로 성공으로 처리된다.
+func (s *Storage) DeleteMany(bucket string, keys []string) (failed map[string]error, err error) {
+	failed = map[string]error{}
+
+	// 검증을 통과한 key → 원래 key
+	prepared := make(map[string]string, len(keys))
+	var batch []types.ObjectIdentifier
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		previous := map[string]int64{}
+		if s.config.DirStats {
+			for _, object := range batch {
+				previous[*object.Key] = s.sizeBefore(bucket, *object.Key)
+			}
+		}
+
+		output, err := s.client.DeleteObjects(s.requestContext(), &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: batch, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range output.Errors {
+			key := aws.ToString(e.Key)
+			failed[prepared[key]] = fmt.Errorf("%s: %s", aws.ToString(e.Code), aws.ToString(e.Message))
+			delete(previous, key)
+		}
+
+		for key, size := range previous {
+			if size >= 0 {
+				s.updateDirStats(bucket, key, size, -1)
+			}
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, key := range keys {
+		validKey, err := s.prepareKey(OpDelete, key)
+		if err != nil {
+			failed[key] = err
+			continue
+		}
+
+		prepared[validKey] = key
+		batch = append(batch, types.ObjectIdentifier{Key: aws.String(validKey)})
+		if len(batch) == maxDeleteKeys {
+			if err = flush(); err != nil {
+				return failed, err
+			}
+		}
+	}
+
+	if err = flush(); err != nil {
+		return failed, err
+	}
+	return failed, nil
+}
+
+// DeletePrefix 는 prefix 아래 모든 객체를 1000개씩 묶어 삭제하고 삭제한 key 목록을 반환한다.
+// dryRun 이 true 이면 삭제하지 않고 삭제될 key 목록만 반환한다.
+// 실수로 버킷 전체를 지우지 않도록 빈 prefix 는 거부한다.
+// Config.GuardedDelete 이면 바로 삭제하지 않고 삭제 목록(manifest)을 저장한 뒤 *ConfirmError 를 반환한다.
+func (s *Storage) DeletePrefix(bucket, prefix string, dryRun ...bool) ([]string, error) {
+	if prefix == "" {
+		return nil, errors.New("empty prefix")
+	}
+
+	if s.config.GuardedDelete && !(len(dryRun) > 0 && dryRun[0]) {
+		return s.planDelete(bucket, prefix)
+	}
+
+	var (
+		deleted []string
+		batch   []string
+		failed  = map[string]error{}
+	)
+
+	flush := func() error {
+		result, err := s.DeleteMany(bucket, batch)
+		if err != nil {
+			return err
+		}
+		for _, key := range batch {
+			if e, ok := result[key]; ok {
+				failed[key] = e
+			} else {
+				deleted = append(deleted, key)
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		if len(dryRun) > 0 && dryRun[0] {
+			deleted = append(deleted, obj.Key)
+			return nil
+		}
+
+		batch = append(batch, obj.Key)
+		if len(batch) == maxDeleteKeys {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	if err != nil {
+		return deleted, err
+	}
+
+	for key, e := range failed {
+		return deleted, fmt.Errorf("%d objects not deleted, %s: %w", len(failed), key, e)
+	}
+	return deleted, nil
+}
+
+// BatchOp 는 BatchResult 를 만든 작업
+type BatchOp string
+
+const (
+	BatchDelete   BatchOp = "delete"
+	BatchUpload   BatchOp = "upload"   // UploadDir
+	BatchDownload BatchOp = "download" // DownloadPrefix
+)
+
+// BatchResult 는 여러 key 에 대한 작업 결과. RetryFailed 에 넘기면 실패한 key 만 다시 실행한다.
+// DeleteBatch 가 반환하며, UploadDir / DownloadPrefix 결과는 UploadBatch / DownloadBatch 로 바꾼다.
+// Sync 는 실행할 때마다 다른 파일만 다시 처리하므로 포함하지 않는다 (실패 후 Sync 를 다시 실행).
+type BatchResult struct {
+	Op        BatchOp
+	Bucket    string
+	Succeeded []string
+	Failed    map[string]error  // key 별 오류, 요청 자체가 실패한 묶음의 key 도 포함
+	Paths     map[string]string // 업로드 / 다운로드의 key 별 로컬 경로
+
+	upload []UploadOption // 재시도에 다시 적용할 업로드 옵션
+}
+
+// Err 는 실패한 key 가 없으면 nil
+func (r *BatchResult) Err() error {
+	for _, key := range r.FailedKeys() {
+		return fmt.Errorf("%d of %d objects failed, %s: %w", len(r.Failed), len(r.Failed)+len(r.Succeeded), key, r.Failed[key])
+	}
+	return nil
+}
+
+// FailedKeys 실패한 key, 정렬됨
+func (r *BatchResult) FailedKeys() []string {
+	return slices.Sorted(maps.Keys(r.Failed))
+}
+
+// DeleteBatch 는 DeleteMany 와 같지만 1000개 묶음의 요청이 실패해도 나머지 묶음을 계속 삭제하고,
+// 모든 key 의 결과를 BatchResult 로 반환한다.
+//
+//	result := store.DeleteBatch("bucket", keys)
+//	for result.Err() != nil && attempt < 3 {
+//		result, _ = store.RetryFailed(ctx, result)
+//	}
+func (s *Storage) DeleteBatch(bucket string, keys []string) *BatchResult {
+	result := &BatchResult{Op: BatchDelete, Bucket: bucket, Failed: map[string]error{}}
+
+	for chunk := range slices.Chunk(keys, maxDeleteKeys) {
+		failed, err := s.DeleteMany(bucket, chunk)
+		for _, key := range chunk {
+			switch {
+			case failed[key] != nil:
+				result.Failed[key] = failed[key]
+			case err != nil:
+				result.Failed[key] = err
+			default:
+				result.Succeeded = append(result.Succeeded, key)
+			}
+		}
+	}
+	return result
+}
+
+// UploadBatch 는 UploadDir 결과를 BatchResult 로 바꾼다.
+// options 는 UploadDir 에 준 것과 같게 넘겨야 재시도에도 적용된다.
+func UploadBatch(bucket string, results []UploadResult, options ...UploadOption) *BatchResult {
+	batch := &BatchResult{Op: BatchUpload, Bucket: bucket, Failed: map[string]error{}, Paths: map[string]string{}, upload: options}
+	for _, result := range results {
+		batch.add(result.Key, result.Path, result.Err)
+	}
+	return batch
+}
+
+// DownloadBatch 는 DownloadPrefix 결과를 BatchResult 로 바꾼다. Overwrite 설정으로 건너뛴 객체는 성공으로 본다.
+// localDir 밖을 가리키는 key(ErrUnsafeKey)는 경로가 없으므로 재시도해도 계속 실패한다.
+func DownloadBatch(bucket string, results []DownloadResult) *BatchResult {
+	batch := &BatchResult{Op: BatchDownload, Bucket: bucket, Failed: map[string]error{}, Paths: map[string]string{}}
+	for _, result := range results {
+		batch.add(result.Key, result.Path, result.Err)
+	}
+	return batch
+}
+
+func (r *BatchResult) add(key, path string, err error) {
+	r.Paths[key] = path
+	if err != nil {
+		r.Failed[key] = err
+	} else {
+		r.Succeeded = append(r.Succeeded, key)
+	}
+}
+
+// RetryFailed 는 result 에서 실패한 key 만 ctx 로 다시 실행한 새 결과를 반환한다.
+// 업로드 / 다운로드는 실패한 항목이 적으므로 하나씩 순서대로 다시 실행한다.
+func (s *Storage) RetryFailed(ctx context.Context, result *BatchResult) (*BatchResult, error) {
+	s = s.WithContext(ctx)
+
+	switch result.Op {
+	case BatchDelete:
+		return s.DeleteBatch(result.Bucket, result.FailedKeys()), nil
+	case BatchUpload, BatchDownload:
+	default:
+		return nil, fmt.Errorf("unknown batch op %q", result.Op)
+	}
+
+	retry := &BatchResult{Op: result.Op, Bucket: result.Bucket, Failed: map[string]error{}, Paths: map[string]string{}, upload: result.upload}
+	for _, key := range result.FailedKeys() {
+		path := result.Paths[key]
+		err := result.Failed[key]
+
+		switch {
+		case path == "":
+			// 경로를 만들 수 없었던 key (ErrUnsafeKey)
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		case result.Op == BatchUpload:
+			err = s.Upload(result.Bucket, key, path, result.upload...)
+		default:
+			err = s.downloadFile(result.Bucket, key, path, time.Time{})
+		}
+		retry.add(key, path, err)
+	}
+	return retry, nil
+}
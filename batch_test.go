@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatchResult(t *testing.T) {
+	result := &BatchResult{Op: BatchDelete, Succeeded: []string{"a"}, Failed: map[string]error{}}
+	if result.Err() != nil {
+		t.Error(result.Err())
+	}
+
+	denied := errors.New("denied")
+	result.Failed["c"] = denied
+	result.Failed["b"] = denied
+	if !slices.Equal(result.FailedKeys(), []string{"b", "c"}) || !errors.Is(result.Err(), denied) {
+		t.Error(result.FailedKeys(), result.Err())
+	}
+
+	if _, err := (&Storage{}).RetryFailed(context.Background(), &BatchResult{Op: "sync"}); err == nil {
+		t.Error("알 수 없는 작업이 허용됨")
+	}
+}
+
+func TestDeleteBatchRetry(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for _, key := range []string{"a", "b", "c"} {
+		fake.put("bucket", key, []byte(key))
+	}
+
+	// 첫 시도에서는 b 만 실패
+	fake.deleteError = func(key string) bool { return key == "b" }
+	result := store.DeleteBatch("bucket", []string{"a", "b", "c"})
+	if !slices.Equal(result.Succeeded, []string{"a", "c"}) || !slices.Equal(result.FailedKeys(), []string{"b"}) || result.Err() == nil {
+		t.Fatalf("first: %+v", result)
+	}
+
+	fake.deleteError = nil
+	retry, err := store.RetryFailed(context.Background(), result)
+	if err != nil || retry.Err() != nil || !slices.Equal(retry.Succeeded, []string{"b"}) {
+		t.Fatalf("retry: %+v, %v", retry, err)
+	}
+	if keys := fake.keys("bucket"); len(keys) != 0 {
+		t.Errorf("remaining %v", keys)
+	}
+}
+
+func TestUploadBatchRetry(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644)
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644)
+
+	var down atomic.Bool
+	down.Store(true)
+	fake.fail = func(r *http.Request) int {
+		if down.Load() && r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/b.txt") {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+
+	results, err := store.UploadDir("bucket", dir, "site/", 2, Options{CacheControl: "no-cache"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := UploadBatch("bucket", results, Options{CacheControl: "no-cache"})
+	if !slices.Equal(result.Succeeded, []string{"site/a.txt"}) || !slices.Equal(result.FailedKeys(), []string{"site/b.txt"}) {
+		t.Fatalf("first: %+v", result)
+	}
+
+	down.Store(false)
+	retry, err := store.RetryFailed(context.Background(), result)
+	if err != nil || retry.Err() != nil || !slices.Equal(retry.Succeeded, []string{"site/b.txt"}) {
+		t.Fatalf("retry: %+v, %v", retry, err)
+	}
+	if object := fake.objects["bucket/site/b.txt"]; string(object.data) != "b" || object.headers.Get("Cache-Control") != "no-cache" {
+		t.Errorf("b.txt: %+v", object)
+	}
+
+	// 취소된 ctx 로는 다시 실행하지 않는다
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if retry, _ = store.RetryFailed(ctx, result); !errors.Is(retry.Failed["site/b.txt"], context.Canceled) {
+		t.Errorf("canceled: %+v", retry)
+	}
+}
+
+func TestDownloadBatchRetry(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "site/a.txt", []byte("a"))
+	fake.put("bucket", "site/b.txt", []byte("b"))
+	fake.put("bucket", "site/../escape.txt", []byte("x"))
+	dir := t.TempDir()
+
+	var down atomic.Bool
+	down.Store(true)
+	fake.fail = func(r *http.Request) int {
+		if down.Load() && r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/b.txt") {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+
+	results, err := store.DownloadPrefix("bucket", "site/", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := DownloadBatch("bucket", results)
+	if !slices.Equal(result.Succeeded, []string{"site/a.txt"}) || !slices.Equal(result.FailedKeys(), []string{"site/../escape.txt", "site/b.txt"}) {
+		t.Fatalf("first: %+v", result)
+	}
+
+	down.Store(false)
+	retry, err := store.RetryFailed(context.Background(), result)
+	if err != nil || !slices.Equal(retry.Succeeded, []string{"site/b.txt"}) || !errors.Is(retry.Failed["site/../escape.txt"], ErrUnsafeKey) {
+		t.Fatalf("retry: %+v, %v", retry, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.txt")); string(data) != "b" {
+		t.Errorf("b.txt = %q", data)
+	}
+}
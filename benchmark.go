@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type BenchmarkOptions struct {
+	Sizes       []int64 // default: 64KB, 1MB, 16MB
+	Concurrency []int   // default: 1, 4, 16
+	Prefix      string  // default: benchmark/
+}
+
+type Measurement struct {
+	Throughput float64       // bytes/sec
+	Latency    time.Duration // 평균
+	MaxLatency time.Duration
+}
+
+type BenchmarkResult struct {
+	Size        int64
+	Concurrency int
+	Upload      Measurement
+	Download    Measurement
+}
+
+// Benchmark 는 크기와 동시성 조합별로 업로드/다운로드 처리량과 지연을 측정한다.
+// 측정에 사용한 객체는 끝나면 삭제한다. 크기와 동시성은 0 보다 커야 한다.
+func (s *Storage) Benchmark(bucket string, options ...BenchmarkOptions) ([]BenchmarkResult, error) {
+	var opt BenchmarkOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	if len(opt.Sizes) == 0 {
+		opt.Sizes = []int64{64 << 10, 1 << 20, 16 << 20}
+	}
+	if len(opt.Concurrency) == 0 {
+		opt.Concurrency = []int{1, 4, 16}
+	}
+	if opt.Prefix == "" {
+		opt.Prefix = "benchmark/"
+	}
+	for _, size := range opt.Sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("benchmark: invalid size %d", size)
+		}
+	}
+	for _, concurrency := range opt.Concurrency {
+		if concurrency <= 0 {
+			return nil, fmt.Errorf("benchmark: invalid concurrency %d", concurrency)
+		}
+	}
+
+	var results []BenchmarkResult
+	for _, size := range opt.Sizes {
+		path, err := randomFile(size)
+		if err != nil {
+			return results, err
+		}
+
+		for _, concurrency := range opt.Concurrency {
+			result, err := s.benchmark(bucket, opt.Prefix, path, size, concurrency)
+			if err != nil {
+				os.Remove(path)
+				return results, err
+			}
+			results = append(results, result)
+		}
+
+		os.Remove(path)
+	}
+
+	return results, nil
+}
+
+func (s *Storage) benchmark(bucket, prefix, path string, size int64, concurrency int) (BenchmarkResult, error) {
+	result := BenchmarkResult{Size: size, Concurrency: concurrency}
+
+	keys := make([]string, concurrency)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s%d-%d-%d", prefix, size, concurrency, i)
+	}
+	defer func() {
+		for _, key := range keys {
+			s.Delete(bucket, key)
+		}
+	}()
+
+	var err error
+	result.Upload, err = measure(keys, size, func(key string) error {
+		return s.Upload(bucket, key, path)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Download, err = measure(keys, size, func(key string) error {
+		output, err := s.getObject(bucket, key)
+		if err != nil {
+			return err
+		}
+		defer output.Body.Close()
+
+		_, err = io.Copy(io.Discard, output.Body)
+		return err
+	})
+
+	return result, err
+}
+
+// measure keys 를 동시에 실행하고 처리량과 지연을 계산
+func measure(keys []string, size int64, fn func(key string) error) (Measurement, error) {
+	var (
+		wg        sync.WaitGroup
+		latencies = make([]time.Duration, len(keys))
+		errs      = make([]error, len(keys))
+		start     = time.Now()
+	)
+
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			begin := time.Now()
+			errs[i] = fn(key)
+			latencies[i] = time.Since(begin)
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	m := Measurement{Throughput: float64(size*int64(len(keys))) / elapsed.Seconds()}
+
+	var total time.Duration
+	for i, latency := range latencies {
+		if errs[i] != nil {
+			return m, errs[i]
+		}
+		total += latency
+		m.MaxLatency = max(m.MaxLatency, latency)
+	}
+	m.Latency = total / time.Duration(len(keys))
+
+	return m, nil
+}
+
+func randomFile(size int64) (string, error) {
+	file, err := os.CreateTemp("", "storage-benchmark-*")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err = io.CopyN(file, rand.Reader, size); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
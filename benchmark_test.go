@@ -0,0 +1,20 @@
+package storage
+
+import "testing"
+
+func TestBenchmarkInvalid(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for _, opt := range []BenchmarkOptions{
+		{Sizes: []int64{1 << 10, 0}},
+		{Sizes: []int64{-1}},
+		{Concurrency: []int{4, 0}},
+		{Concurrency: []int{-1}},
+	} {
+		if _, err := store.Benchmark("bucket", opt); err == nil {
+			t.Errorf("%+v: expected error", opt)
+		}
+	}
+	if keys := fake.keys("bucket"); len(keys) != 0 {
+		t.Errorf("uploaded %v", keys)
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Blackout 은 대량 작업(Batch 전송, 정리 작업 등)을 멈추는 시간대
+// 예: 평일 피크 시간 {Start: "09:00", End: "18:00", Days: 월~금}
+type Blackout struct {
+	Start    string         // "HH:MM"
+	End      string         // "HH:MM", Start 보다 이르면 다음 날까지
+	Days     []time.Weekday // 시작 요일, 비어 있으면 매일
+	Location *time.Location // nil 이면 time.Local
+}
+
+// Remaining 은 t 가 blackout 안에 있으면 끝날 때까지 남은 시간을 반환한다.
+func (b Blackout) Remaining(t time.Time) (time.Duration, bool) {
+	start, err := parseClock(b.Start)
+	if err != nil {
+		return 0, false
+	}
+	end, err := parseClock(b.End)
+	if err != nil {
+		return 0, false
+	}
+
+	location := b.Location
+	if location == nil {
+		location = time.Local
+	}
+	t = t.In(location)
+
+	length := end - start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+
+	// 오늘 또는 어제 시작한 구간 (자정을 넘는 경우)
+	for _, day := range []int{0, -1} {
+		y, m, d := t.AddDate(0, 0, day).Date()
+		from := time.Date(y, m, d, 0, 0, 0, 0, location).Add(start)
+		if len(b.Days) > 0 && !slices.Contains(b.Days, from.Weekday()) {
+			continue
+		}
+		if !t.Before(from) && t.Before(from.Add(length)) {
+			return from.Add(length).Sub(t), true
+		}
+	}
+	return 0, false
+}
+
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock %q: %w", clock, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// blackoutRemaining Config.Blackouts 중 현재 적용되는 가장 긴 남은 시간
+func (s *Storage) blackoutRemaining() time.Duration {
+	var remaining time.Duration
+	now := time.Now()
+	for _, b := range s.config.Blackouts {
+		if d, ok := b.Remaining(now); ok && d > remaining {
+			remaining = d
+		}
+	}
+	return remaining
+}
+
+// WaitBlackout 은 blackout 시간대이면 끝날 때까지 기다린다. 기다린 시간을 반환.
+// 직접 만든 장시간 작업에서 항목마다 호출하면 같은 시간대에 자동으로 멈춘다.
+// WithContext 의 ctx 가 끝나면 기다리지 않고 ctx 의 오류를 반환한다.
+func (s *Storage) WaitBlackout() (time.Duration, error) {
+	ctx := s.requestContext()
+	var waited time.Duration
+	// 연속된 blackout 이 있을 수 있으므로 반복
+	for remaining := s.blackoutRemaining(); remaining > 0; remaining = s.blackoutRemaining() {
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return waited, ctx.Err()
+		case <-timer.C:
+		}
+		waited += remaining
+	}
+	return waited, ctx.Err()
+}
@@ -0,0 +1,57 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestBlackoutRemaining(t *testing.T) {
+	seoul := time.FixedZone("KST", 9*60*60)
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	peak := storage.Blackout{Start: "09:00", End: "18:00", Days: weekdays, Location: seoul}
+	night := storage.Blackout{Start: "23:00", End: "02:00", Location: seoul}
+
+	cases := []struct {
+		blackout  storage.Blackout
+		at        time.Time
+		remaining time.Duration
+		active    bool
+	}{
+		{peak, time.Date(2024, 5, 13, 17, 30, 0, 0, seoul), 30 * time.Minute, true}, // 월요일
+		{peak, time.Date(2024, 5, 13, 18, 0, 0, 0, seoul), 0, false},
+		{peak, time.Date(2024, 5, 12, 12, 0, 0, 0, seoul), 0, false}, // 일요일
+		{night, time.Date(2024, 5, 12, 23, 30, 0, 0, seoul), 150 * time.Minute, true},
+		{night, time.Date(2024, 5, 13, 1, 0, 0, 0, seoul), time.Hour, true},
+		{night, time.Date(2024, 5, 13, 3, 0, 0, 0, seoul), 0, false},
+	}
+
+	for _, c := range cases {
+		remaining, active := c.blackout.Remaining(c.at)
+		if remaining != c.remaining || active != c.active {
+			t.Errorf("%s: got (%s, %v), want (%s, %v)", c.at, remaining, active, c.remaining, c.active)
+		}
+	}
+}
+
+func TestWaitBlackoutContext(t *testing.T) {
+	store, err := storage.New(storage.Config{
+		Endpoint:  "http://127.0.0.1:9000",
+		Blackouts: []storage.Blackout{{Start: "00:00", End: "00:00"}}, // 하루 종일
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	waited, err := store.WithContext(ctx).WaitBlackout()
+	if !errors.Is(err, context.DeadlineExceeded) || waited != 0 {
+		t.Fatalf("got (%s, %v)", waited, err)
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// BucketInfo 는 ListBuckets 결과
+type BucketInfo struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// CreateBucket 은 Config.Region 에 버킷을 만든다. 이미 내 소유의 버킷이면 오류 없이 반환한다.
+// 다른 계정이 이미 사용 중인 이름이면 provider 오류(BucketAlreadyExists)를 반환한다.
+func (s *Storage) CreateBucket(bucket string) error {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return err
+	}
+
+	_, err := s.client.CreateBucket(s.requestContext(), &s3.CreateBucketInput{
+		Bucket:                    aws.String(bucket),
+		CreateBucketConfiguration: bucketConfiguration(s.config.Region),
+	})
+	if errorCode(err) == "BucketAlreadyOwnedByYou" {
+		return nil
+	}
+	return err
+}
+
+// DeleteBucket 은 빈 버킷을 삭제한다. 객체가 남아 있으면 provider 오류(BucketNotEmpty)를 반환한다.
+func (s *Storage) DeleteBucket(bucket string) error {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return err
+	}
+
+	_, err := s.client.DeleteBucket(s.requestContext(), &s3.DeleteBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	return err
+}
+
+// BucketExists 는 HEAD 로 버킷이 있는지 확인한다.
+// 버킷은 있지만 접근 권한이 없으면 false 와 ErrAccessDenied 를 반환한다.
+func (s *Storage) BucketExists(bucket string) (bool, error) {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return false, err
+	}
+
+	_, err := s.client.HeadBucket(s.requestContext(), &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if isNotFound(err) || errors.Is(err, ErrBucketNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListBuckets 는 자격 증명으로 접근할 수 있는 모든 버킷을 반환한다.
+func (s *Storage) ListBuckets() ([]BucketInfo, error) {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return nil, err
+	}
+
+	var (
+		buckets []BucketInfo
+		token   *string
+	)
+	for {
+		output, err := s.client.ListBuckets(s.requestContext(), &s3.ListBucketsInput{
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return buckets, err
+		}
+
+		for _, bucket := range output.Buckets {
+			buckets = append(buckets, BucketInfo{
+				Name:    aws.ToString(bucket.Name),
+				Created: aws.ToTime(bucket.CreationDate),
+			})
+		}
+
+		if aws.ToString(output.ContinuationToken) == "" {
+			return buckets, nil
+		}
+		token = output.ContinuationToken
+	}
+}
+
+// bucketConfiguration 은 region 을 LocationConstraint 로 지정한다.
+// us-east-1 은 지정하면 오히려 거부되고, R2 의 "auto" 는 region 이 아니므로 생략한다.
+func bucketConfiguration(region string) *types.CreateBucketConfiguration {
+	switch region {
+	case "", "auto", "us-east-1":
+		return nil
+	}
+	return &types.CreateBucketConfiguration{LocationConstraint: types.BucketLocationConstraint(region)}
+}
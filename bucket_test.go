@@ -0,0 +1,15 @@
+package storage
+
+import "testing"
+
+func TestBucketConfiguration(t *testing.T) {
+	for _, region := range []string{"", "auto", "us-east-1"} {
+		if c := bucketConfiguration(region); c != nil {
+			t.Errorf("%q: %+v", region, c)
+		}
+	}
+
+	if c := bucketConfiguration("us-west-004"); c == nil || c.LocationConstraint != "us-west-004" {
+		t.Errorf("%+v", c)
+	}
+}
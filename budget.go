@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	awsMiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+var ErrDeadlineBudgetExceeded = errors.New("deadline budget exceeded")
+
+type StepTiming struct {
+	Step     string // sign, connect, transfer, verify, other
+	Duration time.Duration
+}
+
+// DeadlineBudgetError 는 ctx 의 deadline 때문에 요청이 실패했을 때 단계별 소요 시간을 담는다.
+// errors.Is(err, ErrDeadlineBudgetExceeded), errors.Is(err, context.DeadlineExceeded) 모두 true.
+type DeadlineBudgetError struct {
+	Operation string        // 예: PutObject
+	Budget    time.Duration // 요청 시작 시 deadline 까지 남아 있던 시간
+	Elapsed   time.Duration
+	Attempts  int // 재시도 포함 전송 횟수
+	Steps     []StepTiming
+	Err       error
+}
+
+func (e *DeadlineBudgetError) Error() string {
+	steps := make([]string, len(e.Steps))
+	for i, step := range e.Steps {
+		steps[i] = fmt.Sprintf("%s=%s", step.Step, step.Duration.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("%s: deadline budget %s exceeded after %d attempts (%s): %v",
+		e.Operation, e.Budget.Round(time.Millisecond), e.Attempts, strings.Join(steps, " "), e.Err)
+}
+
+func (e *DeadlineBudgetError) Is(target error) bool {
+	return target == ErrDeadlineBudgetExceeded
+}
+
+func (e *DeadlineBudgetError) Unwrap() error {
+	return e.Err
+}
+
+// budget 은 요청 하나(재시도 포함), 또는 withBudget 으로 묶은 작업 전체의 단계별 누적 시간
+type budget struct {
+	mu        sync.Mutex
+	attempts  int
+	sign      time.Duration
+	connect   time.Duration
+	roundTrip time.Duration // connect 포함
+	verify    time.Duration // 다운로드 검증 (checksum 계산, 비교)
+
+	// withBudget 으로 만든 작업 단위 budget
+	start    time.Time
+	deadline time.Time
+}
+
+type (
+	budgetKey    struct{}
+	signStartKey struct{}
+)
+
+func (b *budget) add(target *time.Duration, d time.Duration) {
+	b.mu.Lock()
+	*target += d
+	b.mu.Unlock()
+}
+
+// addVerify 는 검증 시간을 기록한다. b 가 nil(deadline 없음)이면 아무 것도 하지 않는다.
+func (b *budget) addVerify(d time.Duration) {
+	if b != nil {
+		b.add(&b.verify, d)
+	}
+}
+
+// withBudget 은 ctx 에 deadline 이 있으면 Download 처럼 여러 요청과 검증으로 이루어진 작업 전체의 budget 을 붙인다.
+// 그 안의 요청은 따로 보고하지 않고, 작업이 deadline 으로 실패하면 finish 가 한 번 보고한다.
+func (s *Storage) withBudget() (*Storage, *budget) {
+	ctx := s.requestContext()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return s, nil
+	}
+
+	b := &budget{start: time.Now(), deadline: deadline}
+	return s.WithContext(context.WithValue(ctx, budgetKey{}, b)), b
+}
+
+// budgetFrom 은 withBudget 으로 붙인 budget, 없으면 nil
+func budgetFrom(ctx context.Context) *budget {
+	b, _ := ctx.Value(budgetKey{}).(*budget)
+	return b
+}
+
+// finish 는 deadline 으로 실패한 작업의 오류를 *DeadlineBudgetError 로 바꾼다.
+func (b *budget) finish(operation string, err error) error {
+	if b == nil || err == nil {
+		return err
+	}
+	if !errors.Is(err, context.DeadlineExceeded) && time.Now().Before(b.deadline) {
+		return err
+	}
+	return b.report(operation, b.deadline.Sub(b.start), time.Since(b.start), err)
+}
+
+// verifyWriter 는 검증용 digest 에 쓰는 시간을 verify 단계로 기록한다.
+type verifyWriter struct {
+	w io.Writer
+	b *budget
+}
+
+func (v *verifyWriter) Write(p []byte) (int, error) {
+	start := time.Now()
+	n, err := v.w.Write(p)
+	v.b.addVerify(time.Since(start))
+	return n, err
+}
+
+func (b *budget) report(operation string, total, elapsed time.Duration, err error) *DeadlineBudgetError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	transfer := b.roundTrip - b.connect
+	other := elapsed - b.sign - b.roundTrip - b.verify
+
+	return &DeadlineBudgetError{
+		Operation: operation,
+		Budget:    total,
+		Elapsed:   elapsed,
+		Attempts:  b.attempts,
+		Steps: []StepTiming{
+			{"sign", b.sign},
+			{"connect", b.connect},
+			{"transfer", transfer},
+			{"verify", b.verify},
+			{"other", max(other, 0)}, // 직렬화, 재시도 대기 등
+		},
+		Err: err,
+	}
+}
+
+// addBudgetMiddleware 는 ctx 에 deadline 이 있는 요청의 단계별 시간을 기록하고,
+// deadline 으로 실패하면 오류를 *DeadlineBudgetError 로 바꾼다.
+func addBudgetMiddleware(stack *middleware.Stack) error {
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("StorageBudget", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok || budgetFrom(ctx) != nil {
+			// withBudget 으로 묶은 작업은 끝날 때 한 번 보고
+			return next.HandleInitialize(ctx, in)
+		}
+
+		b := &budget{}
+		start := time.Now()
+		out, metadata, err := next.HandleInitialize(context.WithValue(ctx, budgetKey{}, b), in)
+		if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+			err = b.report(awsMiddleware.GetOperationName(ctx), deadline.Sub(start), time.Since(start), err)
+		}
+		return out, metadata, err
+	}), middleware.Before)
+	if err != nil {
+		return err
+	}
+
+	signStart := middleware.FinalizeMiddlewareFunc("StorageBudgetSignStart", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		if b := budgetFrom(ctx); b != nil {
+			b.mu.Lock()
+			b.attempts++
+			b.mu.Unlock()
+			// 병렬 요청이 같은 budget 을 쓰므로 시작 시각은 요청 ctx 에 둔다
+			ctx = context.WithValue(ctx, signStartKey{}, time.Now())
+		}
+		return next.HandleFinalize(ctx, in)
+	})
+
+	signEnd := middleware.FinalizeMiddlewareFunc("StorageBudgetSignEnd", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		b := budgetFrom(ctx)
+		signStart, ok := ctx.Value(signStartKey{}).(time.Time)
+		if b == nil || !ok {
+			return next.HandleFinalize(ctx, in)
+		}
+
+		b.add(&b.sign, time.Since(signStart))
+
+		var getConn time.Time
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			GetConn: func(string) { getConn = time.Now() },
+			GotConn: func(httptrace.GotConnInfo) { b.add(&b.connect, time.Since(getConn)) },
+		})
+		return next.HandleFinalize(ctx, in)
+	})
+
+	// 서명 middleware 가 없는 stack (익명 요청 등) 에서는 서명 시간을 기록하지 않음
+	if stack.Finalize.Insert(signStart, "Signing", middleware.Before) == nil {
+		if err = stack.Finalize.Insert(signEnd, "Signing", middleware.After); err != nil {
+			return err
+		}
+	}
+
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("StorageBudgetTransfer", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (middleware.DeserializeOutput, middleware.Metadata, error) {
+		b := budgetFrom(ctx)
+		if b == nil {
+			return next.HandleDeserialize(ctx, in)
+		}
+
+		start := time.Now()
+		out, metadata, err := next.HandleDeserialize(ctx, in)
+		b.add(&b.roundTrip, time.Since(start))
+		return out, metadata, err
+	}), middleware.After)
+}
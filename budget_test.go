@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeadlineBudgetError(t *testing.T) {
+	b := &budget{
+		attempts:  2,
+		sign:      10 * time.Millisecond,
+		connect:   300 * time.Millisecond,
+		roundTrip: 1500 * time.Millisecond,
+	}
+
+	var err error = b.report("PutObject", 2*time.Second, 2*time.Second, context.DeadlineExceeded)
+	if !errors.Is(err, ErrDeadlineBudgetExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("오류 분류가 잘못됨:", err)
+	}
+
+	var budgetErr *DeadlineBudgetError
+	errors.As(err, &budgetErr)
+
+	want := map[string]time.Duration{
+		"sign":     10 * time.Millisecond,
+		"connect":  300 * time.Millisecond,
+		"transfer": 1200 * time.Millisecond,
+		"other":    490 * time.Millisecond,
+	}
+	for _, step := range budgetErr.Steps {
+		if step.Duration != want[step.Step] {
+			t.Errorf("%s: got %s, want %s", step.Step, step.Duration, want[step.Step])
+		}
+	}
+}
+
+func TestDownloadBudget(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "a.txt", []byte("hello"))
+
+	// deadline 안에 끝나면 그대로
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var out bytes.Buffer
+	if err := store.WithContext(ctx).DownloadWriter("bucket", "a.txt", &out, WithVerifyChecksum()); err != nil || out.String() != "hello" {
+		t.Fatalf("%q, %v", out.String(), err)
+	}
+
+	// 작업 전체를 한 번만 보고
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	err := store.WithContext(expired).Download("bucket", "a.txt", filepath.Join(t.TempDir(), "a.txt"), WithVerifyChecksum())
+	var budgetErr *DeadlineBudgetError
+	if !errors.As(err, &budgetErr) || budgetErr.Operation != "Download" || errors.As(budgetErr.Err, new(*DeadlineBudgetError)) {
+		t.Fatalf("got %v", err)
+	}
+
+	// 검증 시간은 verify 단계로
+	s, b := store.WithContext(expired).withBudget()
+	w := &verifyWriter{w: sha256.New(), b: budgetFrom(s.requestContext())}
+	w.Write(make([]byte, 1<<20))
+	errors.As(b.finish("DownloadWriter", context.DeadlineExceeded), &budgetErr)
+	for _, step := range budgetErr.Steps {
+		if step.Step == "verify" && step.Duration <= 0 {
+			t.Errorf("verify step not recorded: %+v", budgetErr.Steps)
+		}
+	}
+}
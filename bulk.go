@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BulkOptions 는 UpdateMetadataBulk, ApplyHeadersRecursive 설정
+type BulkOptions struct {
+	Concurrency int                         // 동시 복사 수, 기본 16
+	Rate        float64                     // 초당 처리할 최대 객체 수, 0 이면 제한 없음
+	Progress    func(BulkProgress)          // 객체 하나를 처리할 때마다 호출
+	OnError     func(key string, err error) // 실패한 객체, nil 이면 무시하고 계속
+}
+
+type BulkProgress struct {
+	Scanned int64
+	Updated int64
+	Failed  int64
+}
+
+// UpdateMetadataBulk 는 prefix 아래 객체를 모두 순회하며 mutate 가 반환한 변경을 서버 측 복사로 적용한다.
+// mutate 가 nil 또는 빈 map 을 반환하거나 이미 같은 값이면 건너뛴다.
+// map 의 key 가 Cache-Control, Content-Type, Content-Disposition, Content-Encoding, Content-Language 이면 해당 헤더를,
+// ACL(x-amz-acl)이면 canned ACL 을, 그 밖의 key 는 사용자 메타데이터를 바꾼다. 값이 "" 이면 삭제.
+// 개별 객체 실패는 OnError 로 전달하고 계속 진행하며, 목록 조회 실패만 오류로 반환한다.
+func (s *Storage) UpdateMetadataBulk(bucket, prefix string, mutate func(ObjectInfo) map[string]string, options ...BulkOptions) (BulkProgress, error) {
+	var opt BulkOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 16
+	}
+
+	var (
+		scanned, updated, failed atomic.Int64
+		mu                       sync.Mutex // Progress, OnError 호출 직렬화
+		wg                       sync.WaitGroup
+		jobs                     = make(chan ObjectInfo)
+	)
+
+	progress := func() BulkProgress {
+		return BulkProgress{Scanned: scanned.Load(), Updated: updated.Load(), Failed: failed.Load()}
+	}
+
+	for range opt.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range jobs {
+				err := s.applyMetadata(bucket, obj, mutate)
+				scanned.Add(1)
+				switch {
+				case err == errSkipped:
+				case err != nil:
+					failed.Add(1)
+				default:
+					updated.Add(1)
+				}
+
+				mu.Lock()
+				if err != nil && err != errSkipped && opt.OnError != nil {
+					opt.OnError(obj.Key, err)
+				}
+				if opt.Progress != nil {
+					opt.Progress(progress())
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var limit <-chan time.Time
+	if opt.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opt.Rate))
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		if _, err := s.WaitBlackout(); err != nil {
+			return err
+		}
+		if limit != nil {
+			<-limit
+		}
+		jobs <- obj
+		return nil
+	})
+	close(jobs)
+	wg.Wait()
+
+	return progress(), err
+}
+
+// ApplyHeadersRecursive 는 prefix 아래 모든 객체에 headers 를 적용한다 (chmod -R 처럼).
+// 예전 데이터에 Cache-Control, Content-Disposition, ACL 정책을 일괄 적용할 때 사용하며,
+// 이미 같은 값인 객체는 복사하지 않으므로 중단되면 다시 실행하면 된다.
+// headers 형식은 UpdateMetadataBulk 와 같고, 대량 작업이므로 BulkOptions.Rate 로 속도를 제한하는 것을 권장.
+func (s *Storage) ApplyHeadersRecursive(bucket, prefix string, headers map[string]string, options ...BulkOptions) (BulkProgress, error) {
+	return s.UpdateMetadataBulk(bucket, prefix, func(ObjectInfo) map[string]string {
+		return headers
+	}, options...)
+}
+
+// errSkipped mutate 가 변경 사항을 반환하지 않음
+var errSkipped = errors.New("skipped")
+
+func (s *Storage) applyMetadata(bucket string, obj ObjectInfo, mutate func(ObjectInfo) map[string]string) error {
+	changes := mutate(obj)
+	if len(changes) == 0 {
+		return errSkipped
+	}
+
+	return s.replaceMetadata(bucket, obj.Key, func(headers *objectHeaders) {
+		for name, value := range changes {
+			switch http.CanonicalHeaderKey(name) {
+			case "Cache-Control":
+				headers.CacheControl = value
+			case "Content-Type":
+				headers.ContentType = value
+			case "Content-Disposition":
+				headers.ContentDisposition = value
+			case "Content-Encoding":
+				headers.ContentEncoding = value
+			case "Content-Language":
+				headers.ContentLanguage = value
+			case "Acl", "X-Amz-Acl":
+				headers.ACL = value
+			default:
+				name = strings.ToLower(name)
+				if value == "" {
+					delete(headers.Metadata, name)
+				} else {
+					headers.Metadata[name] = value
+				}
+			}
+		}
+	})
+}
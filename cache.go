@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNoPublicURL = errors.New("missing public base url")
+
+type WarmResult struct {
+	Key      string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// WarmCache 는 PublicURL 을 GET 하여 CDN edge 캐시를 미리 채운다.
+// PublicBaseURL 이 없으면 스토리지 기본 공개 주소를 사용하며, 만들 수 없으면(R2 등) ErrNoPublicURL.
+// 결과는 keys 와 같은 순서로 반환된다.
+func (s *Storage) WarmCache(bucket string, keys []string, concurrency int) ([]WarmResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]WarmResult, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for i := range results {
+		target, err := s.PublicURL(bucket, results[i].Key)
+		if err != nil {
+			return nil, err
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *WarmResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := time.Now()
+			result.Status, result.Err = s.warm(target)
+			result.Duration = time.Since(start)
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+func (s *Storage) warm(target string) (int, error) {
+	req, _ := http.NewRequestWithContext(s.requestContext(), http.MethodGet, target, nil)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// 본문을 끝까지 읽어야 edge 에 캐시된다
+	if _, err = io.Copy(io.Discard, resp.Body); err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("warm failed: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// escapeKey "/" 는 유지하고 각 경로 조각만 escape
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestWarmCache(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.EscapedPath())
+		mu.Unlock()
+		if r.URL.Path == "/cdn/bucket/missing.js" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "body")
+	}))
+	defer server.Close()
+
+	store, err := New(Config{Endpoint: "s3.us-west-004.backblazeb2.com", PublicBaseURL: server.URL + "/cdn/{bucket}/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"app.js", "dir/a b.css", "missing.js"}
+	results, err := store.WarmCache("bucket", keys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result.Key != keys[i] {
+			t.Errorf("%d: key %s", i, result.Key)
+		}
+	}
+	if results[0].Status != http.StatusOK || results[0].Err != nil || results[1].Err != nil {
+		t.Errorf("results %+v", results)
+	}
+	if results[2].Status != http.StatusNotFound || results[2].Err == nil {
+		t.Errorf("missing %+v", results[2])
+	}
+
+	// PublicURL 과 같은 주소로 요청
+	want, _ := store.PublicURL("bucket", "dir/a b.css")
+	if want != server.URL+"/cdn/bucket/dir/a%20b.css" {
+		t.Errorf("PublicURL %s", want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 3 {
+		t.Errorf("paths %v", paths)
+	}
+	found := false
+	for _, path := range paths {
+		found = found || server.URL+path == want
+	}
+	if !found {
+		t.Errorf("paths %v, want %s", paths, want)
+	}
+}
+
+func TestWarmCacheNoPublicURL(t *testing.T) {
+	store, err := New(Config{Endpoint: "https://account.r2.cloudflarestorage.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.WarmCache("bucket", []string{"a"}, 1); !errors.Is(err, ErrNoPublicURL) {
+		t.Errorf("R2 without PublicBaseURL: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Capability string
+
+const (
+	CapPresign          Capability = "presign"
+	CapConditionalWrite Capability = "conditional-write" // If-Match / If-None-Match PUT
+	CapMultipartCopy    Capability = "multipart-copy"    // UploadPartCopy
+	CapVersioning       Capability = "versioning"
+	CapObjectLock       Capability = "object-lock"
+	CapNotifications    Capability = "notifications" // S3 API 버킷 알림
+	CapPresignPost      Capability = "presign-post"  // 브라우저 form 업로드 (POST Object)
+)
+
+// S3 API 기준으로 확인된 기능만 포함
+var capabilities = map[SType][]Capability{
+	AWS:   {CapPresign, CapConditionalWrite, CapMultipartCopy, CapVersioning, CapObjectLock, CapNotifications, CapPresignPost},
+	R2:    {CapPresign, CapConditionalWrite, CapMultipartCopy},
+	B2:    {CapPresign, CapMultipartCopy, CapVersioning, CapObjectLock},
+	Other: {CapPresign, CapMultipartCopy, CapPresignPost},
+}
+
+// 문서에 공개된 한도, 기타는 S3 API 기준
+var providerLimits = map[SType]Limits{
+	AWS:   {MaxObjectSize: 5 << 40, MaxPutSize: 5 << 30, MinPartSize: minPartSize, MaxParts: maxPartNumber, MaxDeleteKeys: maxDeleteKeys},
+	R2:    {MaxObjectSize: 5 << 40, MaxPutSize: 5 << 30, MinPartSize: minPartSize, MaxParts: maxPartNumber, MaxDeleteKeys: maxDeleteKeys},
+	B2:    {MaxObjectSize: 10e12, MaxPutSize: 5e9, MinPartSize: minPartSize, MaxParts: maxPartNumber, MaxDeleteKeys: maxDeleteKeys},
+	Other: {MaxObjectSize: 5 << 40, MaxPutSize: 5 << 30, MinPartSize: minPartSize, MaxParts: maxPartNumber, MaxDeleteKeys: maxDeleteKeys},
+}
+
+// Limits 는 스토리지별 크기/개수 한도 (byte)
+type Limits struct {
+	MaxObjectSize int64 `json:"max_object_size"`
+	MaxPutSize    int64 `json:"max_put_size"`  // 단일 PUT, 넘으면 multipart
+	MinPartSize   int64 `json:"min_part_size"` // 마지막 part 제외
+	MaxParts      int   `json:"max_parts"`
+	MaxDeleteKeys int   `json:"max_delete_keys"` // DeleteObjects 요청당
+}
+
+// ProviderCapabilities 는 스토리지 한 종류가 지원하는 기능과 한도
+type ProviderCapabilities struct {
+	Type         SType        `json:"type"`
+	Capabilities []Capability `json:"capabilities"`
+	Limits       Limits       `json:"limits"`
+}
+
+// CapabilityMatrix 는 지원하는 모든 스토리지의 기능과 한도를 AWS, R2, B2, 기타 순으로 반환한다.
+// 문서 생성이나 실행 중 기능 분기에 사용한다.
+func CapabilityMatrix() []ProviderCapabilities {
+	matrix := make([]ProviderCapabilities, 0, len(capabilities))
+	for _, stype := range []SType{AWS, R2, B2, Other} {
+		matrix = append(matrix, providerCapabilities(stype))
+	}
+	return matrix
+}
+
+// Capabilities 는 현재 스토리지의 기능과 한도
+func (s *Storage) Capabilities() ProviderCapabilities {
+	return providerCapabilities(s.Type())
+}
+
+func providerCapabilities(stype SType) ProviderCapabilities {
+	return ProviderCapabilities{
+		Type:         stype,
+		Capabilities: slices.Clone(capabilities[stype]),
+		Limits:       providerLimits[stype],
+	}
+}
+
+// CapabilityError 는 Config.Require 중 현재 스토리지가 지원하지 않는 기능 목록
+type CapabilityError struct {
+	Type      SType
+	Missing   []Capability
+	Supported []Capability
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("%s does not support %s (supported: %s)", e.Type, joinCapabilities(e.Missing), joinCapabilities(e.Supported))
+}
+
+func (s *Storage) Supports(capability Capability) bool {
+	return slices.Contains(capabilities[s.Type()], capability)
+}
+
+func checkCapabilities(stype SType, required []Capability) error {
+	var missing []Capability
+	for _, capability := range required {
+		if !slices.Contains(capabilities[stype], capability) {
+			missing = append(missing, capability)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &CapabilityError{Type: stype, Missing: missing, Supported: capabilities[stype]}
+}
+
+func joinCapabilities(list []Capability) string {
+	names := make([]string, len(list))
+	for i, capability := range list {
+		names[i] = string(capability)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,56 @@
+package storage_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestRequireCapability(t *testing.T) {
+	_, err := storage.New(storage.Config{
+		Endpoint: "<account-id>.r2.cloudflarestorage.com",
+		Require:  []storage.Capability{storage.CapPresign, storage.CapVersioning},
+	})
+
+	var capErr *storage.CapabilityError
+	if !errors.As(err, &capErr) {
+		t.Fatal("CapabilityError 가 아님:", err)
+	}
+
+	if capErr.Type != storage.R2 || len(capErr.Missing) != 1 || capErr.Missing[0] != storage.CapVersioning {
+		t.Error("누락 기능이 잘못됨:", capErr)
+	}
+
+	store, err := storage.New(storage.Config{
+		Endpoint: "s3.us-west-004.backblazeb2.com",
+		Require:  []storage.Capability{storage.CapVersioning},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !store.Supports(storage.CapObjectLock) || store.Supports(storage.CapNotifications) {
+		t.Error("B2 기능 판단이 잘못됨")
+	}
+}
+
+func TestCapabilityMatrix(t *testing.T) {
+	matrix := storage.CapabilityMatrix()
+	if len(matrix) != 4 || matrix[0].Type != storage.AWS || matrix[3].Type != storage.Other {
+		t.Fatalf("%+v", matrix)
+	}
+
+	for _, provider := range matrix {
+		if len(provider.Capabilities) == 0 || provider.Limits.MaxObjectSize == 0 || provider.Limits.MaxDeleteKeys != 1000 {
+			t.Errorf("%+v", provider)
+		}
+	}
+
+	// 반환값을 바꿔도 내부 목록은 그대로
+	matrix[1].Capabilities[0] = storage.CapVersioning
+	if slices.Contains(storage.CapabilityMatrix()[1].Capabilities, storage.CapVersioning) {
+		t.Error("내부 목록이 바뀜")
+	}
+}
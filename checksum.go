@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrChecksumNotSupported = errors.New("checksum algorithm not supported")
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+)
+
+type ChecksumAlgorithm string
+
+const (
+	ChecksumCRC32  ChecksumAlgorithm = "CRC32"
+	ChecksumCRC32C ChecksumAlgorithm = "CRC32C"
+	ChecksumSHA1   ChecksumAlgorithm = "SHA1"
+	ChecksumSHA256 ChecksumAlgorithm = "SHA256"
+	ChecksumMD5    ChecksumAlgorithm = "MD5" // ETag 비교 (multipart 업로드는 검증 생략)
+)
+
+// 스토리지별 지원 알고리즘, 첫 번째가 권장값
+var checksumSupport = map[SType][]ChecksumAlgorithm{
+	AWS:   {ChecksumCRC32C, ChecksumCRC32, ChecksumSHA1, ChecksumSHA256, ChecksumMD5},
+	R2:    {ChecksumCRC32C, ChecksumCRC32, ChecksumSHA1, ChecksumSHA256, ChecksumMD5},
+	B2:    {ChecksumSHA1, ChecksumMD5},
+	Other: {ChecksumMD5},
+}
+
+// PreferredChecksum 은 현재 스토리지에서 권장하는 체크섬 알고리즘
+func (s *Storage) PreferredChecksum() ChecksumAlgorithm {
+	return checksumSupport[s.Type()][0]
+}
+
+func (s *Storage) checkChecksum(algorithm ChecksumAlgorithm) error {
+	if algorithm == "" || slices.Contains(checksumSupport[s.Type()], algorithm) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s on %s", ErrChecksumNotSupported, algorithm, s.Type())
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumSupport(t *testing.T) {
+	endpoints := map[SType]string{
+		AWS:   "s3.us-east-1.amazonaws.com",
+		R2:    "https://account.r2.cloudflarestorage.com",
+		B2:    "s3.us-west-004.backblazeb2.com",
+		Other: "https://minio.example.com",
+	}
+	tests := []struct {
+		stype     SType
+		preferred ChecksumAlgorithm
+		supported []ChecksumAlgorithm
+		rejected  []ChecksumAlgorithm
+	}{
+		{AWS, ChecksumCRC32C, []ChecksumAlgorithm{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256, ChecksumMD5}, nil},
+		{R2, ChecksumCRC32C, []ChecksumAlgorithm{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256, ChecksumMD5}, nil},
+		{B2, ChecksumSHA1, []ChecksumAlgorithm{ChecksumSHA1, ChecksumMD5}, []ChecksumAlgorithm{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA256}},
+		{Other, ChecksumMD5, []ChecksumAlgorithm{ChecksumMD5}, []ChecksumAlgorithm{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256}},
+	}
+
+	for _, test := range tests {
+		store, err := New(Config{Endpoint: endpoints[test.stype], Region: "us-east-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if store.Type() != test.stype {
+			t.Fatalf("%s: type %s", endpoints[test.stype], store.Type())
+		}
+		if got := store.PreferredChecksum(); got != test.preferred {
+			t.Errorf("%s: preferred %s, want %s", test.stype, got, test.preferred)
+		}
+		for _, algorithm := range append(test.supported, "") {
+			if err := store.checkChecksum(algorithm); err != nil {
+				t.Errorf("%s: %s: %v", test.stype, algorithm, err)
+			}
+		}
+		for _, algorithm := range append(test.rejected, "XXHASH") {
+			if err := store.checkChecksum(algorithm); !errors.Is(err, ErrChecksumNotSupported) {
+				t.Errorf("%s: %s: %v", test.stype, algorithm, err)
+			}
+		}
+	}
+}
+
+func TestUploadChecksumB2(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Endpoint: "s3.us-west-004.backblazeb2.com"})
+
+	var checksum, trailer string
+	fake.fail = func(r *http.Request) int {
+		if r.Method == http.MethodPut {
+			checksum, trailer = r.Header.Get("X-Amz-Checksum-Sha1"), r.Header.Get("X-Amz-Trailer")
+		}
+		return 0
+	}
+
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("hello"), 0o644)
+	if err := store.Upload("bucket", "a.txt", path, WithChecksum(ChecksumSHA1)); err != nil {
+		t.Fatal(err)
+	}
+	// 헤더 또는 aws-chunked trailer 로 전송, fake 가 값을 확인한다
+	sum := sha1.Sum([]byte("hello"))
+	if checksum != base64.StdEncoding.EncodeToString(sum[:]) && trailer != "x-amz-checksum-sha1" {
+		t.Errorf("sha1 checksum not sent: header %q, trailer %q", checksum, trailer)
+	}
+	if string(fake.get("bucket", "a.txt")) != "hello" {
+		t.Errorf("stored %q", fake.get("bucket", "a.txt"))
+	}
+
+	// B2 가 지원하지 않는 알고리즘은 요청 전에 거부
+	checksum, trailer = "", ""
+	if err := store.Upload("bucket", "b.txt", path, WithChecksum(ChecksumCRC32C)); !errors.Is(err, ErrChecksumNotSupported) {
+		t.Errorf("crc32c on B2: %v", err)
+	}
+	if fake.get("bucket", "b.txt") != nil {
+		t.Error("거부된 업로드가 저장됨")
+	}
+}
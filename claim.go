@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	claimPrefix       = "processing/"
+	failedPrefix      = "failed/"
+	defaultClaimLease = 15 * time.Minute
+)
+
+var (
+	ErrNoWork    = errors.New("no unclaimed object")
+	ErrClaimLost = errors.New("claim lost to another worker")
+)
+
+// errClaimed 는 다른 워커의 선점이 아직 유효함
+var errClaimed = errors.New("claimed by another worker")
+
+// Claim 은 워커가 선점한 객체. Release, Complete, Fail 중 하나로 끝내야 한다.
+// Expires 가 지나면 다른 워커가 다시 선점할 수 있으므로, 오래 걸리는 작업은 Extend 로 연장한다.
+type Claim struct {
+	Bucket  string
+	Key     string
+	Claimed time.Time
+	Expires time.Time
+
+	storage *Storage
+	etag    string // 마커 ETag
+}
+
+// claimMarker 는 "processing/<key>" 마커 내용
+type claimMarker struct {
+	Claimed time.Time `json:"claimed"`
+	Expires time.Time `json:"expires"`
+}
+
+// Claim 은 prefix 아래에서 아직 선점되지 않은 객체 하나를 원자적으로 선점한다.
+// "processing/<key>" 마커를 조건부 PUT(If-None-Match: *)으로 만들어
+// 여러 워커가 동시에 호출해도 한 워커만 성공한다.
+// 선점은 lease(기본 15분) 동안 유효하며, 워커가 죽어 만료된 마커는 If-Match 조건부 PUT 으로 가져온다.
+// 조건부 쓰기(CapConditionalWrite)를 지원하지 않는 스토리지에서는 *CapabilityError 를 반환한다.
+func (s *Storage) Claim(bucket, prefix string, lease ...time.Duration) (*Claim, error) {
+	if err := checkCapabilities(s.Type(), []Capability{CapConditionalWrite}); err != nil {
+		return nil, err
+	}
+
+	ttl := defaultClaimLease
+	if len(lease) > 0 && lease[0] > 0 {
+		ttl = lease[0]
+	}
+
+	var token []string
+	for {
+		keys, next, err := s.List(bucket, prefix, 1000, token...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, claimPrefix) || strings.HasPrefix(key, failedPrefix) {
+				continue
+			}
+
+			claim, err := s.claim(bucket, key, ttl)
+			if isPreconditionFailed(err) || errors.Is(err, errClaimed) || isNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return claim, nil
+		}
+
+		if next == "" {
+			return nil, ErrNoWork
+		}
+		token = []string{next}
+	}
+}
+
+// claim 은 마커가 없으면 만들고, 만료된 마커가 있으면 가져온다.
+func (s *Storage) claim(bucket, key string, lease time.Duration) (*Claim, error) {
+	marker := claimPrefix + key
+	if err := s.config.Policy.Allow(OpPut, marker); err != nil {
+		return nil, err
+	}
+
+	claim := &Claim{Bucket: bucket, Key: key, Claimed: time.Now().UTC(), storage: s}
+	err := claim.putMarker(lease, "")
+	if err == nil {
+		return claim, nil
+	}
+	if !isPreconditionFailed(err) {
+		return nil, err
+	}
+
+	current, etag, err := s.readClaimMarker(bucket, marker, lease)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(current.Expires) {
+		return nil, errClaimed
+	}
+
+	// 만료된 마커, 그 사이 다른 워커가 가져가면 If-Match 가 실패한다
+	if err := claim.putMarker(lease, etag); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// putMarker 는 etag 가 비어 있으면 마커가 없을 때만, 있으면 etag 가 일치할 때만 쓴다.
+func (c *Claim) putMarker(lease time.Duration, etag string) error {
+	expires := time.Now().Add(lease).UTC()
+	data, _ := json.Marshal(claimMarker{Claimed: c.Claimed, Expires: expires})
+
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(c.Bucket),
+		Key:           aws.String(claimPrefix + c.Key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/json"),
+	}
+	if etag == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(etag)
+	}
+
+	output, err := c.storage.client.PutObject(c.storage.requestContext(), input)
+	if err != nil {
+		return err
+	}
+
+	c.etag = aws.ToString(output.ETag)
+	c.Expires = expires
+	return nil
+}
+
+// readClaimMarker 는 마커 내용과 ETag. 이전 형식(선점 시각만 기록)은 선점 시각 + lease 를 만료 시각으로 본다.
+func (s *Storage) readClaimMarker(bucket, marker string, lease time.Duration) (claimMarker, string, error) {
+	output, err := s.client.GetObject(s.requestContext(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(marker),
+	})
+	if err != nil {
+		return claimMarker{}, "", err
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return claimMarker{}, "", err
+	}
+
+	var current claimMarker
+	if json.Unmarshal(data, &current) != nil {
+		claimed, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+		current = claimMarker{Claimed: claimed, Expires: claimed.Add(lease)}
+	}
+	return current, aws.ToString(output.ETag), nil
+}
+
+// Extend 는 선점을 지금부터 lease 동안 연장한다.
+// 그 사이 만료되어 다른 워커가 가져갔으면 ErrClaimLost 를 반환하며, 이 선점으로는 더 진행하지 않아야 한다.
+func (c *Claim) Extend(lease time.Duration) error {
+	err := c.putMarker(lease, c.etag)
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: %w", ErrClaimLost, err)
+	}
+	return err
+}
+
+// Release 는 처리를 포기하고 다른 워커가 다시 가져갈 수 있게 한다.
+// 다른 워커가 이미 가져갔으면 그 워커의 마커를 지우지 않고 ErrClaimLost 를 반환한다.
+func (c *Claim) Release() error {
+	if err := c.owned(); err != nil {
+		return err
+	}
+	return c.storage.Delete(c.Bucket, claimPrefix+c.Key)
+}
+
+// Complete 는 원본 객체와 마커를 삭제한다. 선점을 잃었으면 아무 것도 지우지 않고 ErrClaimLost 를 반환한다.
+func (c *Claim) Complete() error {
+	if err := c.owned(); err != nil {
+		return err
+	}
+	if err := c.storage.Delete(c.Bucket, c.Key); err != nil {
+		return err
+	}
+	return c.storage.Delete(c.Bucket, claimPrefix+c.Key)
+}
+
+// owned 는 마커 ETag 가 이 선점이 마지막으로 쓴 값과 같은지 확인한다.
+// 조건부 DELETE 를 지원하지 않는 provider 가 있어 HEAD 로 비교한다.
+func (c *Claim) owned() error {
+	s := c.storage
+	head, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
+		Bucket: aws.String(c.Bucket),
+		Key:    aws.String(claimPrefix + c.Key),
+	})
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if err == nil && strings.Trim(aws.ToString(head.ETag), `"`) == strings.Trim(c.etag, `"`) {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrClaimLost, &smithy.GenericAPIError{Code: "PreconditionFailed", Message: "claim marker changed"})
+}
+
+// Fail 은 원본 객체를 "failed/<key>" 로 옮기고 마커를 삭제한다. 선점을 잃었으면 ErrClaimLost.
+func (c *Claim) Fail() error {
+	s := c.storage
+	if err := s.config.Policy.Allow(OpPut, failedPrefix+c.Key); err != nil {
+		return err
+	}
+	if err := c.owned(); err != nil {
+		return err
+	}
+
+	_, err := s.client.CopyObject(s.requestContext(), &s3.CopyObjectInput{
+		Bucket:     aws.String(c.Bucket),
+		Key:        aws.String(failedPrefix + c.Key),
+		CopySource: aws.String(copySource(c.Bucket, c.Key)),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Complete()
+}
+
+func copySource(bucket, key string) string {
+	return bucket + "/" + url.PathEscape(key)
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClaimExclusive(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "jobs/a", []byte("a"))
+	fake.put("bucket", "jobs/.dirstats.json", []byte("{}"))
+
+	first, err := store.Claim("bucket", "jobs/")
+	if err != nil || first.Key != "jobs/a" {
+		t.Fatalf("claim %+v, %v", first, err)
+	}
+	if !first.Expires.After(time.Now().Add(defaultClaimLease - time.Minute)) {
+		t.Fatalf("expires %v", first.Expires)
+	}
+
+	// 사이드카 객체는 작업으로 내주지 않는다
+	if _, err := store.Claim("bucket", "jobs/"); !errors.Is(err, ErrNoWork) {
+		t.Fatalf("expected ErrNoWork, got %v", err)
+	}
+
+	if err := first.Complete(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.get("bucket", "jobs/a") != nil || fake.get("bucket", claimPrefix+"jobs/a") != nil {
+		t.Fatalf("remaining %v", fake.keys("bucket"))
+	}
+}
+
+func TestClaimExpiredTakeover(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "a", []byte("a"))
+
+	crashed, err := store.Claim("bucket", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 워커가 죽어 만료된 마커
+	data, _ := json.Marshal(claimMarker{Claimed: crashed.Claimed, Expires: time.Now().Add(-time.Second)})
+	fake.put("bucket", claimPrefix+"a", data)
+
+	claim, err := store.Claim("bucket", "")
+	if err != nil || claim.Key != "a" {
+		t.Fatalf("takeover %+v, %v", claim, err)
+	}
+
+	// 가져간 뒤에는 이전 워커가 연장할 수 없다
+	if err := crashed.Extend(time.Minute); !errors.Is(err, ErrClaimLost) || !isPreconditionFailed(err) {
+		t.Fatalf("expected ErrClaimLost, got %v", err)
+	}
+
+	// 이전 워커는 새 워커의 마커와 원본을 지우지 못한다
+	for name, finish := range map[string]func() error{"release": crashed.Release, "complete": crashed.Complete, "fail": crashed.Fail} {
+		if err := finish(); !errors.Is(err, ErrClaimLost) || !isPreconditionFailed(err) {
+			t.Errorf("%s: expected ErrClaimLost, got %v", name, err)
+		}
+	}
+	if fake.get("bucket", "a") == nil || fake.get("bucket", claimPrefix+"a") == nil || fake.get("bucket", failedPrefix+"a") != nil {
+		t.Fatalf("stale claim changed objects: %v", fake.keys("bucket"))
+	}
+	if err := claim.Extend(time.Hour); err != nil || claim.Expires.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("extend %v, %v", claim.Expires, err)
+	}
+
+	// 이전 형식 마커는 선점 시각 + lease 까지 유효
+	fake.put("bucket", claimPrefix+"a", []byte(time.Now().UTC().Format(time.RFC3339)))
+	if _, err := store.Claim("bucket", ""); !errors.Is(err, ErrNoWork) {
+		t.Fatalf("expected ErrNoWork, got %v", err)
+	}
+	fake.put("bucket", claimPrefix+"a", []byte(time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)))
+	if _, err := store.Claim("bucket", ""); err != nil {
+		t.Fatalf("old marker not taken over: %v", err)
+	}
+}
+
+func TestClaimFail(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "a", []byte("a"))
+
+	claim, err := store.Claim("bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := claim.Fail(); err != nil {
+		t.Fatal(err)
+	}
+	if string(fake.get("bucket", failedPrefix+"a")) != "a" || fake.get("bucket", "a") != nil {
+		t.Fatalf("remaining %v", fake.keys("bucket"))
+	}
+
+	// 실패한 객체는 다시 내주지 않는다
+	if _, err := store.Claim("bucket", ""); !errors.Is(err, ErrNoWork) {
+		t.Fatalf("expected ErrNoWork, got %v", err)
+	}
+
+	b2, _ := newFakeStorage(t, Config{Endpoint: "https://s3.us-west-004.backblazeb2.com"})
+	var capability *CapabilityError
+	if _, err := b2.Claim("bucket", ""); !errors.As(err, &capability) {
+		t.Errorf("expected CapabilityError, got %v", err)
+	}
+}
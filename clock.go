@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go/middleware"
+	smithyHttp "github.com/aws/smithy-go/transport/http"
+)
+
+// 이보다 작은 차이는 Date 헤더(초 단위)와 전송 지연 오차로 보고 무시
+const skewThreshold = 30 * time.Second
+
+// clockSkew 는 응답 Date 헤더로 측정한 서버 시각 - 로컬 시각
+type clockSkew struct {
+	offset atomic.Int64
+}
+
+func (c *clockSkew) get() time.Duration {
+	return time.Duration(c.offset.Load())
+}
+
+// observe 응답마다 다시 측정하므로 로컬 시계가 맞춰지면 0 으로 돌아간다.
+func (c *clockSkew) observe(date string, now time.Time) {
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	skew := server.Sub(now)
+	if skew > -skewThreshold && skew < skewThreshold {
+		skew = 0
+	}
+	c.offset.Store(int64(skew))
+}
+
+// now 서버 기준 현재 시각
+func (c *clockSkew) now() time.Time {
+	return time.Now().Add(c.get())
+}
+
+// addMiddleware 는 오류 응답(RequestTimeTooSkewed, SignatureDoesNotMatch 등)을 포함한 모든 응답의 Date 헤더를 기록한다.
+func (c *clockSkew) addMiddleware(stack *middleware.Stack) error {
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("StorageClockSkew", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (middleware.DeserializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleDeserialize(ctx, in)
+		if resp, ok := out.RawResponse.(*smithyHttp.Response); ok && resp != nil && resp.Response != nil {
+			c.observe(resp.Header.Get("Date"), time.Now())
+		}
+		return out, metadata, err
+	}), middleware.Before)
+}
+
+// skewSigner 는 측정한 차이만큼 서명 시각을 보정한다.
+// 차이가 없으면 SDK 가 넘긴 시각을 그대로 사용한다.
+type skewSigner struct {
+	signer *v4.Signer
+	skew   *clockSkew
+}
+
+func (s *skewSigner) signingTime(signingTime time.Time) time.Time {
+	if s.skew.get() == 0 {
+		return signingTime
+	}
+	return s.skew.now()
+}
+
+func (s *skewSigner) SignHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, region string, signingTime time.Time, optFns ...func(*v4.SignerOptions)) error {
+	return s.signer.SignHTTP(ctx, credentials, r, payloadHash, service, region, s.signingTime(signingTime), optFns...)
+}
+
+func (s *skewSigner) PresignHTTP(ctx context.Context, credentials aws.Credentials, r *http.Request, payloadHash, service, region string, signingTime time.Time, optFns ...func(*v4.SignerOptions)) (string, http.Header, error) {
+	return s.signer.PresignHTTP(ctx, credentials, r, payloadHash, service, region, s.signingTime(signingTime), optFns...)
+}
+
+// ClockSkew 는 마지막 응답으로 측정한 서버 시각과 로컬 시각의 차이. 30초 미만이면 0.
+func (s *Storage) ClockSkew() time.Duration {
+	return s.skew.get()
+}
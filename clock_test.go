@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	var skew clockSkew
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	skew.observe(now.Add(10*time.Minute).Format(http.TimeFormat), now)
+	if skew.get() != 10*time.Minute {
+		t.Error(skew.get())
+	}
+
+	// 작은 차이는 무시하고, 시계가 맞춰지면 0 으로 돌아감
+	skew.observe(now.Add(5*time.Second).Format(http.TimeFormat), now)
+	if skew.get() != 0 {
+		t.Error(skew.get())
+	}
+
+	skew.observe(now.Add(-time.Hour).Format(http.TimeFormat), now)
+	skew.observe("invalid", now)
+	if skew.get() != -time.Hour {
+		t.Error(skew.get())
+	}
+
+	signer := &skewSigner{skew: &clockSkew{}}
+	if got := signer.signingTime(now); !got.Equal(now) {
+		t.Error("차이가 없으면 SDK 시각을 그대로 사용해야 함:", got)
+	}
+}
@@ -0,0 +1,118 @@
+// storage 는 go-storage 패키지의 운영용 CLI
+//
+//	storage bench -bucket <bucket>
+//
+// 접속 정보는 .env 또는 환경 변수(ENDPOINT, ACCESS_KEY_ID, SECRET_ACCESS_KEY)에서 읽는다.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pro200/go-config"
+	"github.com/pro200/go-storage"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "bench":
+		err = bench(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: storage bench -bucket <bucket> [-sizes 65536,1048576] [-concurrency 1,4]")
+	os.Exit(2)
+}
+
+func open() (*storage.Storage, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.New(storage.Config{
+		Endpoint:        cfg.Get("ENDPOINT"),
+		AccessKeyID:     cfg.Get("ACCESS_KEY_ID"),
+		SecretAccessKey: cfg.Get("SECRET_ACCESS_KEY"),
+	})
+}
+
+func bench(args []string) error {
+	var (
+		flags       = flag.NewFlagSet("bench", flag.ExitOnError)
+		bucket      = flags.String("bucket", "", "bucket name")
+		sizes       = flags.String("sizes", "", "object sizes in bytes, comma separated")
+		concurrency = flags.String("concurrency", "", "concurrency levels, comma separated")
+	)
+	flags.Parse(args)
+
+	if *bucket == "" {
+		usage()
+	}
+
+	var options storage.BenchmarkOptions
+	for _, v := range split(*sizes) {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return fmt.Errorf("invalid size %q: must be positive", v)
+		}
+		options.Sizes = append(options.Sizes, n)
+	}
+	for _, v := range split(*concurrency) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return fmt.Errorf("invalid concurrency %q: must be positive", v)
+		}
+		options.Concurrency = append(options.Concurrency, n)
+	}
+
+	store, err := open()
+	if err != nil {
+		return err
+	}
+
+	results, err := store.Benchmark(*bucket, options)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SIZE\tCONC\tUP MB/s\tUP AVG\tUP MAX\tDOWN MB/s\tDOWN AVG\tDOWN MAX")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%d\t%.2f\t%s\t%s\t%.2f\t%s\t%s\n",
+			r.Size, r.Concurrency,
+			r.Upload.Throughput/1e6, r.Upload.Latency, r.Upload.MaxLatency,
+			r.Download.Throughput/1e6, r.Download.Latency, r.Download.MaxLatency)
+	}
+	return w.Flush()
+}
+
+func split(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Codec 은 PutObjectAs / GetObjectAs 가 객체 본문을 직렬화하는 방식.
+// protobuf, msgpack 등은 이 interface 를 구현해 넘긴다.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	ContentType() string // 객체 Content-Type 으로 저장, 읽을 때 비교한다.
+}
+
+var (
+	JSONCodec Codec = jsonCodec{}
+	GobCodec  Codec = gobCodec{}
+)
+
+var ErrCodecMismatch = errors.New("object content type does not match codec")
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) ContentType() string                { return "application/json" }
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) ContentType() string { return "application/x-gob" }
+
+// PutObjectAs 는 v 를 codec 으로 직렬화해 업로드한다. Content-Type 은 options 와 관계없이 codec 의 값.
+func PutObjectAs[T any](s *Storage, bucket, key string, v T, codec Codec, options ...UploadOption) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", key, err)
+	}
+
+	options = append(options, WithContentType(codec.ContentType()))
+	return s.UploadReader(bucket, key, bytes.NewReader(data), options...)
+}
+
+// GetObjectAs 는 PutObjectAs 로 올린 객체를 읽어 codec 으로 역직렬화한다.
+// 객체의 Content-Type 이 codec 과 다르면 ErrCodecMismatch, 받은 본문이 크기나 ETag(단일 PUT 의 MD5)와 다르면 *MismatchError.
+func GetObjectAs[T any](s *Storage, bucket, key string, codec Codec) (T, error) {
+	var v T
+
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return v, err
+	}
+	defer output.Body.Close()
+
+	if contentType := aws.ToString(output.ContentType); !sameMediaType(contentType, codec.ContentType()) {
+		return v, fmt.Errorf("%w: %s is %s, want %s", ErrCodecMismatch, key, contentType, codec.ContentType())
+	}
+
+	opt := newDownloadOptions(nil)
+	opt.expectStored(output.ETag, output.ContentLength, nil)
+
+	d := opt.newDigest()
+	data, err := io.ReadAll(io.TeeReader(output.Body, d))
+	if err != nil {
+		return v, err
+	}
+	if err = opt.verifyDigest(key, d); err != nil {
+		return v, err
+	}
+
+	if err = codec.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("unmarshal %s: %w", key, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodecs(t *testing.T) {
+	type event struct {
+		ID   int
+		Tags []string
+	}
+	want := event{ID: 7, Tags: []string{"a", "b"}}
+
+	for _, codec := range []Codec{JSONCodec, GobCodec} {
+		data, err := codec.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got event
+		if err = codec.Unmarshal(data, &got); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: %+v %v", codec.ContentType(), got, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConfigFile 은 설정 파일에 쓸 수 있는 Config 필드
+// Policy, Transport 처럼 코드로만 지정할 수 있는 필드는 제외.
+// LoadConfig 는 yaml 태그로 YAML 을 읽으며, 다른 YAML 라이브러리로 ConfigFile 을 읽어도 된다.
+type ConfigFile struct {
+	Endpoint        string       `json:"endpoint" yaml:"endpoint"`
+	Region          string       `json:"region" yaml:"region"`
+	AccessKeyID     string       `json:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string       `json:"secret_access_key" yaml:"secret_access_key"`
+	PublicBaseURL   string       `json:"public_base_url" yaml:"public_base_url"`
+	Require         []Capability `json:"require" yaml:"require"`
+	TruncateKeys    bool         `json:"truncate_keys" yaml:"truncate_keys"`
+	CreatedBy       string       `json:"created_by" yaml:"created_by"`
+	DirStats        bool         `json:"dir_stats" yaml:"dir_stats"`
+	GuardedDelete   bool         `json:"guarded_delete" yaml:"guarded_delete"`
+	SigningRegion   string       `json:"signing_region" yaml:"signing_region"`
+	Proxy           string       `json:"proxy" yaml:"proxy"`
+	MaxAttempts     int          `json:"max_attempts" yaml:"max_attempts"`
+}
+
+// LoadConfig 는 설정 파일을 읽어 Config 를 만든다. 확장자가 .yaml / .yml 이면 YAML, 그 밖에는 JSON.
+// Config 자체의 JSON 형식(Go 필드 이름)은 바꾸지 않도록 별도 함수로 둔다.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var file ConfigFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = unmarshalConfigYAML(data, &file)
+	default:
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return file.Config()
+}
+
+// unmarshalConfigYAML 은 ConfigFile 에 필요한 YAML 만 읽는다 (의존성을 추가하지 않기 위해).
+// 한 단계 "key: value", 따옴표 문자열, 주석, [a, b] 또는 "- a" 목록을 지원하고 모르는 key 는 무시한다.
+func unmarshalConfigYAML(data []byte, file *ConfigFile) error {
+	v := reflect.ValueOf(file).Elem()
+	fields := map[string]reflect.Value{}
+	for i := range v.NumField() {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		fields[name] = v.Field(i)
+	}
+
+	var (
+		list    reflect.Value // "- a" 항목을 받을 목록 필드
+		skipped bool          // 모르는 key 의 목록
+	)
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			if skipped {
+				continue
+			}
+			if !list.IsValid() {
+				return fmt.Errorf("line %d: unexpected list item", n+1)
+			}
+			value, err := yamlScalar(item)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n+1, err)
+			}
+			list.Set(reflect.Append(list, reflect.ValueOf(value).Convert(list.Type().Elem())))
+			continue
+		}
+
+		if line != trimmed {
+			return fmt.Errorf("line %d: nested mapping not supported", n+1)
+		}
+		key, raw, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return fmt.Errorf("line %d: expected key: value", n+1)
+		}
+
+		list = reflect.Value{}
+		field, known := fields[strings.TrimSpace(key)]
+		raw = strings.TrimSpace(raw)
+		if skipped = !known; skipped {
+			continue
+		}
+		if err := setYAMLField(field, raw); err != nil {
+			return fmt.Errorf("line %d: %s: %w", n+1, key, err)
+		}
+		if field.Kind() == reflect.Slice && stripComment(raw) == "" {
+			list = field
+		}
+	}
+	return nil
+}
+
+func setYAMLField(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Slice {
+		raw = stripComment(raw)
+		if raw == "" {
+			return nil
+		}
+		inner, ok := strings.CutPrefix(raw, "[")
+		if inner, ok = strings.CutSuffix(inner, "]"); !ok {
+			return fmt.Errorf("expected [a, b] list")
+		}
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		for item := range strings.SplitSeq(inner, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			value, err := yamlScalar(item)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.Append(field, reflect.ValueOf(value).Convert(field.Type().Elem())))
+		}
+		return nil
+	}
+
+	value, err := yamlScalar(raw)
+	if err != nil {
+		return err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(i))
+	}
+	return nil
+}
+
+// yamlScalar 는 따옴표를 벗기고 주석을 뗀 값
+func yamlScalar(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		end := strings.LastIndex(raw, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return strconv.Unquote(raw[:end+1])
+	case strings.HasPrefix(raw, "'"):
+		end := strings.LastIndex(raw, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return strings.ReplaceAll(raw[1:end], "''", "'"), nil
+	}
+	return stripComment(raw), nil
+}
+
+// stripComment 는 " #" 뒤의 주석을 뗀다.
+func stripComment(raw string) string {
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(raw)
+}
+
+// Config 는 문자열 값의 ${ENV_VAR} 를 환경 변수로, file:// 로 시작하는 값을 파일 내용으로 바꾼 Config.
+func (f ConfigFile) Config() (Config, error) {
+	for _, field := range []*string{
+		&f.Endpoint, &f.Region, &f.AccessKeyID, &f.SecretAccessKey,
+		&f.PublicBaseURL, &f.CreatedBy, &f.SigningRegion, &f.Proxy,
+	} {
+		value, err := resolveSecret(*field)
+		if err != nil {
+			return Config{}, err
+		}
+		*field = value
+	}
+
+	return Config{
+		Endpoint:        f.Endpoint,
+		Region:          f.Region,
+		AccessKeyID:     f.AccessKeyID,
+		SecretAccessKey: f.SecretAccessKey,
+		PublicBaseURL:   f.PublicBaseURL,
+		Require:         f.Require,
+		TruncateKeys:    f.TruncateKeys,
+		CreatedBy:       f.CreatedBy,
+		DirStats:        f.DirStats,
+		GuardedDelete:   f.GuardedDelete,
+		SigningRegion:   f.SigningRegion,
+		Proxy:           f.Proxy,
+		MaxAttempts:     f.MaxAttempts,
+	}, nil
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveSecret file://<path> 는 파일 내용(끝 줄바꿈 제거), ${ENV_VAR} 는 환경 변수 값
+// 없는 환경 변수나 읽을 수 없는 파일은 빈 값 대신 오류를 반환한다.
+func resolveSecret(value string) (string, error) {
+	if path, ok := strings.CutPrefix(value, "file://"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	var err error
+	value = envPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := envPattern.FindStringSubmatch(match)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("environment variable not set: %s", name)
+		}
+		return env
+	})
+	return value, err
+}
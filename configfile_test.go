@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("TEST_STORAGE_KEY_ID", "key-id")
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secret, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "storage.json")
+	data := `{
+		"endpoint": "account.r2.cloudflarestorage.com",
+		"access_key_id": "${TEST_STORAGE_KEY_ID}",
+		"secret_access_key": "file://` + secret + `",
+		"require": ["presign"],
+		"dir_stats": true
+	}`
+	os.WriteFile(path, []byte(data), 0o600)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.AccessKeyID != "key-id" || config.SecretAccessKey != "s3cr3t" || !config.DirStats ||
+		len(config.Require) != 1 || config.Require[0] != CapPresign {
+		t.Errorf("%+v", config)
+	}
+
+	if _, err := (ConfigFile{AccessKeyID: "${TEST_STORAGE_MISSING}"}).Config(); err == nil {
+		t.Error("없는 환경 변수는 오류여야 함")
+	}
+}
+
+func TestConfigJSONFieldNames(t *testing.T) {
+	// Config 의 JSON 형식은 Go 필드 이름 그대로
+	var config Config
+	data := `{"Endpoint": "account.r2.cloudflarestorage.com", "AccessKeyID": "key-id", "PartSize": 16777216}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Endpoint != "account.r2.cloudflarestorage.com" || config.AccessKeyID != "key-id" || config.PartSize != 16<<20 {
+		t.Errorf("%+v", config)
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	t.Setenv("TEST_STORAGE_KEY_ID", "key-id")
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret")
+	os.WriteFile(secret, []byte("s3cr3t\n"), 0o600)
+
+	data := `# storage 설정
+---
+endpoint: account.r2.cloudflarestorage.com # R2
+access_key_id: "${TEST_STORAGE_KEY_ID}"
+secret_access_key: 'file://` + secret + `'
+created_by: "image #1"
+require: [presign, conditional-write]
+dir_stats: true
+max_attempts: 5
+unknown:
+  - ignored
+`
+	for _, name := range []string{"storage.yaml", "storage.YML"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(data), 0o600)
+
+		config, err := LoadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Endpoint != "account.r2.cloudflarestorage.com" || config.AccessKeyID != "key-id" || config.SecretAccessKey != "s3cr3t" ||
+			config.CreatedBy != "image #1" || !config.DirStats || config.MaxAttempts != 5 ||
+			len(config.Require) != 2 || config.Require[0] != CapPresign || config.Require[1] != CapConditionalWrite {
+			t.Errorf("%s: %+v", name, config)
+		}
+	}
+
+	// 블록 형식 목록
+	var file ConfigFile
+	if err := unmarshalConfigYAML([]byte("require:\n  - presign\n  - 'conditional-write'\ntruncate_keys: false\n"), &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Require) != 2 || file.Require[1] != CapConditionalWrite {
+		t.Errorf("%+v", file)
+	}
+
+	for _, bad := range []string{"dir_stats: maybe", "max_attempts: many", "endpoint", "proxy:\n  host: x", "- presign", `region: "open`} {
+		if err := unmarshalConfigYAML([]byte(bad), &ConfigFile{}); err == nil {
+			t.Errorf("%q: 오류여야 함", bad)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	maxCopySize  = 5 << 30   // 단일 CopyObject 최대 크기
+	copyPartSize = 512 << 20 // multipart 복사 part 크기
+)
+
+// Copy 는 객체를 서버 측에서 복사한다. 다른 bucket 으로도 복사할 수 있다 (같은 계정/엔드포인트).
+// 5GB 를 넘는 객체는 UploadPartCopy 로 나누어 복사한다.
+// 헤더와 메타데이터는 원본을 따르며, options 를 지정하면 그 값만 바꾼다 (WithMetadata 는 원본 메타데이터에 추가).
+func (s *Storage) Copy(srcBucket, srcKey, dstBucket, dstKey string, options ...UploadOption) error {
+	srcKey, err := s.prepareKey(OpGet, srcKey)
+	if err != nil {
+		return err
+	}
+	dstKey, err = s.prepareKey(OpPut, dstKey)
+	if err != nil {
+		return err
+	}
+
+	head, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return err
+	}
+	size := aws.ToInt64(head.ContentLength)
+
+	var opt Options
+	for _, option := range options {
+		option.applyUpload(&opt)
+	}
+	headers, storageClass := copyHeaders(head, &opt)
+
+	if size > maxCopySize {
+		return s.copyMultipart(srcBucket, srcKey, dstBucket, dstKey, head, headers, storageClass)
+	}
+
+	previous := s.sizeBefore(dstBucket, dstKey)
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(dstBucket),
+		Key:               aws.String(dstKey),
+		CopySource:        aws.String(copySource(srcBucket, srcKey)),
+		CopySourceIfMatch: head.ETag,
+	}
+	if len(options) > 0 {
+		input.MetadataDirective = types.MetadataDirectiveReplace
+		input.Metadata = headers.Metadata
+		input.ContentType = optionalString(headers.ContentType)
+		input.CacheControl = optionalString(headers.CacheControl)
+		input.ContentDisposition = optionalString(headers.ContentDisposition)
+		input.ContentEncoding = optionalString(headers.ContentEncoding)
+		input.ContentLanguage = optionalString(headers.ContentLanguage)
+		input.StorageClass = storageClass
+	}
+
+	_, err = s.client.CopyObject(s.requestContext(), input)
+	if err != nil {
+		return err
+	}
+
+	s.updateDirStats(dstBucket, dstKey, previous, size)
+	return nil
+}
+
+// Move 는 Copy 후 원본을 삭제한다. 복사에 실패하면 원본은 그대로 남는다.
+func (s *Storage) Move(srcBucket, srcKey, dstBucket, dstKey string) error {
+	if srcBucket == dstBucket && srcKey == dstKey {
+		return nil
+	}
+
+	if err := s.Copy(srcBucket, srcKey, dstBucket, dstKey); err != nil {
+		return err
+	}
+	return s.Delete(srcBucket, srcKey)
+}
+
+// copyHeaders 원본 헤더에 opt 에서 지정한 값을 덮어쓴다.
+func copyHeaders(head *s3.HeadObjectOutput, opt *Options) (*objectHeaders, types.StorageClass) {
+	headers := &objectHeaders{
+		Metadata:           maps.Clone(head.Metadata),
+		ContentType:        aws.ToString(head.ContentType),
+		CacheControl:       aws.ToString(head.CacheControl),
+		ContentDisposition: aws.ToString(head.ContentDisposition),
+		ContentEncoding:    aws.ToString(head.ContentEncoding),
+		ContentLanguage:    aws.ToString(head.ContentLanguage),
+	}
+
+	for name, value := range opt.Metadata {
+		if headers.Metadata == nil {
+			headers.Metadata = map[string]string{}
+		}
+		headers.Metadata[strings.ToLower(name)] = value
+	}
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&headers.ContentType, opt.ContentType},
+		{&headers.CacheControl, opt.CacheControl},
+		{&headers.ContentDisposition, opt.ContentDisposition},
+		{&headers.ContentEncoding, opt.ContentEncoding},
+		{&headers.ContentLanguage, opt.ContentLanguage},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+
+	storageClass := head.StorageClass
+	if opt.StorageClass != "" {
+		storageClass = types.StorageClass(opt.StorageClass)
+	}
+	return headers, storageClass
+}
+
+func (s *Storage) copyMultipart(srcBucket, srcKey, dstBucket, dstKey string, head *s3.HeadObjectOutput, headers *objectHeaders, storageClass types.StorageClass) error {
+	created, err := s.client.CreateMultipartUpload(s.requestContext(), &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(dstBucket),
+		Key:                aws.String(dstKey),
+		ContentType:        optionalString(headers.ContentType),
+		CacheControl:       optionalString(headers.CacheControl),
+		ContentDisposition: optionalString(headers.ContentDisposition),
+		ContentEncoding:    optionalString(headers.ContentEncoding),
+		ContentLanguage:    optionalString(headers.ContentLanguage),
+		StorageClass:       storageClass,
+		Metadata:           headers.Metadata,
+		ACL:                types.ObjectCannedACL(headers.ACL),
+		GrantRead:          optionalString(headers.grants.Read),
+		GrantReadACP:       optionalString(headers.grants.ReadACP),
+		GrantWriteACP:      optionalString(headers.grants.WriteACP),
+		GrantFullControl:   optionalString(headers.grants.FullControl),
+	})
+	if err != nil {
+		return err
+	}
+
+	state := &UploadState{
+		Bucket:   dstBucket,
+		Key:      dstKey,
+		UploadID: aws.ToString(created.UploadId),
+	}
+
+	size := aws.ToInt64(head.ContentLength)
+	state.PartSize = copyPartSizeFor(size)
+
+	for offset := int64(0); offset < size; offset += state.PartSize {
+		length := min(state.PartSize, size-offset)
+		number := int32(offset/state.PartSize) + 1
+
+		output, err := s.client.UploadPartCopy(s.requestContext(), &s3.UploadPartCopyInput{
+			Bucket:            aws.String(dstBucket),
+			Key:               aws.String(dstKey),
+			UploadId:          created.UploadId,
+			PartNumber:        aws.Int32(number),
+			CopySource:        aws.String(copySource(srcBucket, srcKey)),
+			CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+			CopySourceIfMatch: head.ETag,
+		})
+		if err != nil {
+			s.AbortMultipart(state)
+			return err
+		}
+
+		state.addPart(UploadedPart{
+			Number: number,
+			ETag:   aws.ToString(output.CopyPartResult.ETag),
+			Offset: offset,
+			Size:   length,
+		})
+	}
+
+	// CompleteMultipart 가 part 크기 합계로 updateDirStats 를 호출한다
+	if err = s.CompleteMultipart(state); err != nil {
+		s.AbortMultipart(state)
+		return err
+	}
+	return nil
+}
+
+// copyPartSizeFor 는 size 를 최대 10000개 part 로 나누는 part 크기 (최소 copyPartSize)
+func copyPartSizeFor(size int64) int64 {
+	return max(copyPartSize, (size+maxPartNumber-1)/maxPartNumber)
+}
+
+// Rename 은 같은 bucket 안에서 key 를 바꾼다 (Move).
+func (s *Storage) Rename(bucket, oldKey, newKey string) error {
+	return s.Move(bucket, oldKey, bucket, newKey)
+}
+
+// RenamePrefix 는 oldPrefix 아래 모든 객체를 newPrefix 아래로 옮기고 옮긴 객체 수를 반환한다.
+// 목록을 먼저 모두 받은 뒤 옮기므로, 도중에 실패하면 다시 호출하여 나머지를 옮길 수 있다.
+func (s *Storage) RenamePrefix(bucket, oldPrefix, newPrefix string) (int, error) {
+	if oldPrefix == "" {
+		return 0, errors.New("empty prefix")
+	}
+	if strings.HasPrefix(newPrefix, oldPrefix) {
+		return 0, fmt.Errorf("new prefix %q is inside old prefix %q", newPrefix, oldPrefix)
+	}
+
+	var keys []string
+	err := s.Walk(bucket, oldPrefix, func(obj ObjectInfo) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, key := range keys {
+		if _, err = s.WaitBlackout(); err != nil {
+			return i, err
+		}
+		if err = s.Rename(bucket, key, newPrefix+strings.TrimPrefix(key, oldPrefix)); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestCopyHeaders(t *testing.T) {
+	head := &s3.HeadObjectOutput{
+		Metadata:     map[string]string{"owner": "a"},
+		ContentType:  aws.String("image/png"),
+		CacheControl: aws.String("no-cache"),
+		StorageClass: types.StorageClassStandardIa,
+	}
+
+	// 지정하지 않으면 원본 그대로
+	headers, storageClass := copyHeaders(head, &Options{})
+	if headers.ContentType != "image/png" || headers.CacheControl != "no-cache" || headers.Metadata["owner"] != "a" || storageClass != types.StorageClassStandardIa {
+		t.Fatalf("%+v %s", headers, storageClass)
+	}
+
+	headers, storageClass = copyHeaders(head, &Options{
+		Metadata:     map[string]string{"Reviewed": "yes"},
+		CacheControl: "max-age=60",
+		StorageClass: "GLACIER",
+	})
+	if headers.ContentType != "image/png" || headers.CacheControl != "max-age=60" || storageClass != types.StorageClassGlacier {
+		t.Fatalf("%+v %s", headers, storageClass)
+	}
+	if headers.Metadata["owner"] != "a" || headers.Metadata["reviewed"] != "yes" {
+		t.Fatalf("metadata %v", headers.Metadata)
+	}
+
+	headers, _ = copyHeaders(head, &Options{ContentType: "image/webp"})
+	if headers.ContentType != "image/webp" || headers.CacheControl != "no-cache" {
+		t.Fatalf("%+v", headers)
+	}
+
+	// 원본 메타데이터는 바꾸지 않음
+	if len(head.Metadata) != 1 {
+		t.Fatalf("source metadata changed: %v", head.Metadata)
+	}
+}
+
+func TestCopyPartSizeFor(t *testing.T) {
+	for _, size := range []int64{maxCopySize + 1, 1 << 40, 5 << 40} {
+		partSize := copyPartSizeFor(size)
+		parts := (size + partSize - 1) / partSize
+		if partSize < copyPartSize || parts > maxPartNumber {
+			t.Errorf("%d: part size %d, %d parts", size, partSize, parts)
+		}
+	}
+	if got := copyPartSizeFor(maxCopySize + 1); got != copyPartSize {
+		t.Errorf("got %d", got)
+	}
+}
+
+func TestCopyMultipartDirStats(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{DirStats: true})
+	fake.put("bucket", "src/a", []byte("0123456789"))
+
+	head, err := store.client.HeadObject(store.requestContext(), &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("src/a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers, storageClass := copyHeaders(head, &Options{})
+	if err := store.copyMultipart("bucket", "src/a", "bucket", "dst/a", head, headers, storageClass); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(fake.get("bucket", "dst/a")) != "0123456789" {
+		t.Fatalf("copied %q", fake.get("bucket", "dst/a"))
+	}
+	stats, err := store.DirStats("bucket", "dst/")
+	if err != nil || stats.Count != 1 || stats.Bytes != 10 {
+		t.Fatalf("stats %+v, %v", stats, err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	if err := store.putBytes("bucket", "a.txt", []byte("a"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Rename("bucket", "a.txt", "a.txt"); err != nil || fake.get("bucket", "a.txt") == nil {
+		t.Fatalf("same key: %v", err)
+	}
+	if err := store.Rename("bucket", "a.txt", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.keys("bucket"); !slices.Equal(got, []string{"b.txt"}) || fake.objects["bucket/b.txt"].headers.Get("Content-Type") != "text/plain" {
+		t.Errorf("keys %v", got)
+	}
+	if err := store.Rename("bucket", "missing", "c.txt"); !isNotFound(err) {
+		t.Errorf("missing: %v", err)
+	}
+}
+
+func TestRenamePrefix(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for i := range 4 {
+		fake.put("bucket", fmt.Sprintf("old/%d", i), []byte{byte(i)})
+	}
+	fake.put("bucket", "older/x", []byte("x"))
+
+	for _, c := range []struct{ oldPrefix, newPrefix string }{
+		{"", "new/"},
+		{"old/", "old/sub/"},
+		{"old/", "old/"},
+	} {
+		if _, err := store.RenamePrefix("bucket", c.oldPrefix, c.newPrefix); err == nil {
+			t.Errorf("%q → %q: 허용됨", c.oldPrefix, c.newPrefix)
+		}
+	}
+	if len(fake.keys("bucket")) != 5 {
+		t.Fatal("거부된 호출이 객체를 바꿈")
+	}
+
+	// old/2 복사가 실패하면 앞의 두 개만 옮기고 멈춘다
+	fake.fail = func(r *http.Request) int {
+		if copySourceName(r) == "bucket/old/2" {
+			return http.StatusInternalServerError
+		}
+		return 0
+	}
+	moved, err := store.RenamePrefix("bucket", "old/", "new/")
+	if err == nil || moved != 2 {
+		t.Fatalf("moved %d, %v", moved, err)
+	}
+	want := []string{"new/0", "new/1", "old/2", "old/3", "older/x"}
+	if got := fake.keys("bucket"); !slices.Equal(got, want) {
+		t.Fatalf("after failure %v", got)
+	}
+
+	// 다시 호출하면 나머지를 옮긴다
+	fake.fail = nil
+	if moved, err = store.RenamePrefix("bucket", "old/", "new/"); err != nil || moved != 2 {
+		t.Fatalf("retry moved %d, %v", moved, err)
+	}
+	want = []string{"new/0", "new/1", "new/2", "new/3", "older/x"}
+	if got := fake.keys("bucket"); !slices.Equal(got, want) || fake.get("bucket", "new/3")[0] != 3 {
+		t.Errorf("after retry %v", got)
+	}
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/pro200/go-utils"
+)
+
+const (
+	minPartSize      = 5 << 20 // S3 multipart 최소 part 크기 (마지막 part 제외)
+	defaultBlockSize = 8 << 20
+	blockIndexSuffix = ".blocks"
+)
+
+// blockIndex 는 UploadDelta 가 객체 옆(<key>.blocks)에 저장하는 블록 체크섬 목록
+type blockIndex struct {
+	BlockSize int64    `json:"block_size"`
+	Size      int64    `json:"size"`
+	ETag      string   `json:"etag"` // 인덱스를 만들 때의 객체 ETag
+	Blocks    []string `json:"blocks"`
+}
+
+// UploadDelta 는 이전 업로드 때 저장한 블록 체크섬과 비교해 바뀐 블록만 전송하고,
+// 바뀌지 않은 블록은 UploadPartCopy 로 서버에서 재조합한다.
+// 블록이 10000개(multipart part 최대 수)를 넘지 않도록 큰 파일은 blockSize 를 늘린다.
+// 반환값은 실제로 전송한 바이트 수.
+func (s *Storage) UploadDelta(bucket, key, path string, blockSize int64) (int64, error) {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if stat.Size() == 0 {
+		return 0, errors.New("zero size file")
+	}
+	blockSize = deltaBlockSize(blockSize, stat.Size())
+
+	local, err := hashBlocks(file, stat.Size(), blockSize)
+	if err != nil {
+		return 0, err
+	}
+
+	previous := s.loadBlockIndex(bucket, key, blockSize)
+	before := s.sizeBefore(bucket, key)
+
+	etag, err := s.uploadBlocks(bucket, key, path, file, local, previous)
+	if err != nil {
+		return 0, err
+	}
+
+	local.ETag = etag
+	data, err := json.Marshal(local)
+	if err != nil {
+		return 0, err
+	}
+
+	// 객체는 이미 바뀌었으므로 인덱스 저장에 실패해도 통계는 반영한다
+	s.updateDirStats(bucket, key, before, stat.Size())
+
+	if err = s.putBytes(bucket, key+blockIndexSuffix, data, "application/json"); err != nil {
+		return 0, err
+	}
+
+	var sent int64
+	for i := range local.Blocks {
+		if !previous.same(local, i) {
+			sent += blockLength(local, i)
+		}
+	}
+	return sent, nil
+}
+
+func (s *Storage) uploadBlocks(bucket, key, path string, file *os.File, local, previous *blockIndex) (string, error) {
+	ctx := s.requestContext()
+
+	created, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(utils.ContentType(path)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	parts, err := s.uploadBlockParts(ctx, bucket, key, created.UploadId, file, local, previous)
+	if err != nil {
+		s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		})
+		return "", err
+	}
+
+	completed, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(completed.ETag), nil
+}
+
+func (s *Storage) uploadBlockParts(ctx context.Context, bucket, key string, uploadID *string, file *os.File, local, previous *blockIndex) ([]types.CompletedPart, error) {
+	parts := make([]types.CompletedPart, 0, len(local.Blocks))
+
+	for i := range local.Blocks {
+		var (
+			number = aws.Int32(int32(i + 1))
+			offset = int64(i) * local.BlockSize
+			length = blockLength(local, i)
+			etag   *string
+		)
+
+		if previous.same(local, i) {
+			output, err := s.client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+				Bucket:            aws.String(bucket),
+				Key:               aws.String(key),
+				UploadId:          uploadID,
+				PartNumber:        number,
+				CopySource:        aws.String(copySource(bucket, key)),
+				CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+				CopySourceIfMatch: aws.String(previous.ETag),
+			})
+			if err != nil {
+				return nil, err
+			}
+			etag = output.CopyPartResult.ETag
+		} else {
+			output, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:        aws.String(bucket),
+				Key:           aws.String(key),
+				UploadId:      uploadID,
+				PartNumber:    number,
+				Body:          io.NewSectionReader(file, offset, length),
+				ContentLength: aws.Int64(length),
+			})
+			if err != nil {
+				return nil, err
+			}
+			etag = output.ETag
+		}
+
+		parts = append(parts, types.CompletedPart{ETag: etag, PartNumber: number})
+	}
+
+	return parts, nil
+}
+
+// loadBlockIndex 인덱스가 없거나 객체가 그 뒤에 바뀌었으면 nil
+func (s *Storage) loadBlockIndex(bucket, key string, blockSize int64) *blockIndex {
+	data, err := s.getBytes(bucket, key+blockIndexSuffix)
+	if err != nil {
+		return nil
+	}
+
+	var index blockIndex
+	if json.Unmarshal(data, &index) != nil || index.BlockSize != blockSize {
+		return nil
+	}
+
+	info, err := s.Info(bucket, key)
+	if err != nil || aws.ToString(info.ETag) != index.ETag {
+		return nil
+	}
+
+	return &index
+}
+
+// deltaBlockSize 는 5MB 미만이면 기본값, 블록이 10000개를 넘으면 10000개가 되도록 늘린 블록 크기
+func deltaBlockSize(blockSize, size int64) int64 {
+	if blockSize < minPartSize {
+		blockSize = defaultBlockSize
+	}
+	return max(blockSize, (size+maxPartNumber-1)/maxPartNumber)
+}
+
+func (b *blockIndex) same(local *blockIndex, i int) bool {
+	return b != nil && i < len(b.Blocks) && b.Blocks[i] == local.Blocks[i]
+}
+
+func blockLength(index *blockIndex, i int) int64 {
+	return min(index.BlockSize, index.Size-int64(i)*index.BlockSize)
+}
+
+func hashBlocks(r io.ReaderAt, size, blockSize int64) (*blockIndex, error) {
+	index := &blockIndex{BlockSize: blockSize, Size: size}
+
+	for offset := int64(0); offset < size; offset += blockSize {
+		hash := sha256.New()
+		if _, err := io.Copy(hash, io.NewSectionReader(r, offset, blockSize)); err != nil {
+			return nil, err
+		}
+		index.Blocks = append(index.Blocks, hex.EncodeToString(hash.Sum(nil)))
+	}
+
+	return index, nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHashBlocks(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 25)
+	index, err := hashBlocks(bytes.NewReader(data), int64(len(data)), 10)
+	if err != nil || len(index.Blocks) != 3 || blockLength(index, 2) != 5 {
+		t.Fatalf("%+v, %v", index, err)
+	}
+	if index.Blocks[0] != index.Blocks[1] || index.Blocks[1] == index.Blocks[2] {
+		t.Fatalf("blocks %v", index.Blocks)
+	}
+
+	// 두 번째 블록만 바뀐 파일
+	data[15] = 'b'
+	changed, _ := hashBlocks(bytes.NewReader(data), int64(len(data)), 10)
+	for i, want := range []bool{true, false, true} {
+		if index.same(changed, i) != want {
+			t.Errorf("block %d: same %v", i, !want)
+		}
+	}
+
+	// 인덱스가 없거나 더 짧으면 전송
+	var none *blockIndex
+	if none.same(changed, 0) || (&blockIndex{Blocks: index.Blocks[:1]}).same(changed, 2) {
+		t.Error("missing block treated as same")
+	}
+}
+
+func TestDeltaBlockSize(t *testing.T) {
+	if got := deltaBlockSize(0, 1<<30); got != defaultBlockSize {
+		t.Errorf("default %d", got)
+	}
+	size := int64(200 << 30)
+	if got := deltaBlockSize(minPartSize, size); (size+got-1)/got > maxPartNumber {
+		t.Errorf("%d blocks", (size+got-1)/got)
+	}
+}
+
+func TestUploadDelta(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	data := make([]byte, 2*minPartSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	path := filepath.Join(t.TempDir(), "data.bin")
+	os.WriteFile(path, data, 0o644)
+
+	sent, err := store.UploadDelta("bucket", "data.bin", path, minPartSize)
+	if err != nil || sent != int64(len(data)) {
+		t.Fatalf("first upload %d, %v", sent, err)
+	}
+
+	// 두 번째 블록만 바꾸면 그 블록만 전송
+	data[minPartSize+1]++
+	os.WriteFile(path, data, 0o644)
+	sent, err = store.UploadDelta("bucket", "data.bin", path, minPartSize)
+	if err != nil || sent != minPartSize {
+		t.Fatalf("second upload %d, %v", sent, err)
+	}
+	if !bytes.Equal(fake.get("bucket", "data.bin"), data) {
+		t.Fatal("object differs from file")
+	}
+
+	if _, err := store.UploadDelta("bucket", "", path, minPartSize); err == nil {
+		t.Error("expected invalid key error")
+	}
+}
+
+func TestUploadDeltaDirStats(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{DirStats: true})
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	os.WriteFile(path, bytes.Repeat([]byte("a"), 100), 0o644)
+	if _, err := store.UploadDelta("bucket", "db/data.bin", path, minPartSize); err != nil {
+		t.Fatal(err)
+	}
+
+	// 다시 올리면 이전 크기를 빼고 새 크기를 더한다
+	os.WriteFile(path, bytes.Repeat([]byte("a"), 150), 0o644)
+	if _, err := store.UploadDelta("bucket", "db/data.bin", path, minPartSize); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := store.DirStats("bucket", "db/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 1 || stats.Bytes != 150 || stats.NewestKey != "db/data.bin" {
+		t.Fatalf("stats %+v", stats)
+	}
+
+	// 블록 인덱스는 목록에 나오지 않는다
+	keys, _, err := store.List("bucket", "db/", 100)
+	if err != nil || !slices.Equal(keys, []string{"db/data.bin"}) {
+		t.Fatalf("keys %v, %v", keys, err)
+	}
+}
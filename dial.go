@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DialFunc 는 Config.DialContext 형식, net.Dialer.DialContext 와 같다.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// StaticHosts 는 host 를 지정한 IP(또는 다른 host)로 바꿔 연결하는 DialFunc 를 반환한다.
+// 이름 해석이 안 되는 폐쇄망에서 사용한다. TLS 인증서는 원래 host 이름으로 검증한다.
+//
+//	DialContext: storage.StaticHosts(map[string]string{
+//		"<account-id>.r2.cloudflarestorage.com": "10.0.0.12",
+//	})
+func StaticHosts(hosts map[string]string) DialFunc {
+	mapped := make(map[string]string, len(hosts))
+	for host, target := range hosts {
+		mapped[strings.ToLower(host)] = target
+	}
+
+	var dialer net.Dialer
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if target, ok := mapped[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(target, port)
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+// transport 는 Config.DialContext, Config.Proxy 를 적용한 transport. 둘 다 없으면 nil.
+// Config.Transport 를 지정하면 그것을 그대로 사용한다.
+func (c Config) transport() (http.RoundTripper, error) {
+	if c.Transport != nil {
+		return c.Transport, nil
+	}
+	if c.DialContext == nil && c.Proxy == "" {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.DialContext != nil {
+		transport.DialContext = c.DialContext
+	}
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return transport, nil
+}
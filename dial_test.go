@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestStaticHosts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	dial := StaticHosts(map[string]string{"Storage.Internal": "127.0.0.1"})
+
+	conn, err := dial(context.Background(), "tcp", net.JoinHostPort("storage.internal", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestConfigTransport(t *testing.T) {
+	if transport, err := (Config{}).transport(); transport != nil || err != nil {
+		t.Error(transport, err)
+	}
+
+	transport, err := Config{Proxy: "socks5://127.0.0.1:1080"}.transport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, _ := transport.(*http.Transport).Proxy(&http.Request{})
+	if proxy == nil || proxy.Scheme != "socks5" {
+		t.Error(proxy)
+	}
+
+	if _, err = (Config{Proxy: "127.0.0.1:1080"}).transport(); err == nil {
+		t.Error("scheme 없는 proxy 가 허용됨")
+	}
+}
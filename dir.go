@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UploadResult 는 UploadDir 의 파일별 결과
+type UploadResult struct {
+	Path     string // 로컬 파일 경로
+	Key      string
+	Size     int64
+	Duration time.Duration
+	Err      error
+}
+
+// UploadDir 은 localDir 아래 모든 일반 파일을 keyPrefix + 상대 경로(/ 구분) key 로 concurrency 개씩 동시에 업로드한다.
+// options 는 모든 파일에 적용되며, Content-Type 을 지정하지 않으면 파일마다 확장자로 추론한다.
+// 결과는 경로 순으로 반환되고 파일별 실패는 Err 에 담긴다. 디렉터리를 읽지 못하면 업로드 전에 오류를 반환한다.
+func (s *Storage) UploadDir(bucket, localDir, keyPrefix string, concurrency int, options ...UploadOption) ([]UploadResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results, err := dirFiles(localDir, keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for i := range results {
+		if _, err = s.WaitBlackout(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *UploadResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := time.Now()
+			result.Err = s.Upload(bucket, result.Key, result.Path, options...)
+			result.Duration = time.Since(start)
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// dirFiles 는 localDir 아래 일반 파일 목록, symlink 는 따라가지 않는다.
+func dirFiles(localDir, keyPrefix string) ([]UploadResult, error) {
+	var files []UploadResult
+	err := filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, UploadResult{
+			Path: p,
+			Key:  keyPrefix + path.Clean(filepath.ToSlash(rel)),
+			Size: info.Size(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+// Overwrite 는 DownloadPrefix 가 이미 있는 로컬 파일을 다루는 방식
+type Overwrite int
+
+const (
+	OverwriteAlways    Overwrite = iota
+	OverwriteNever               // 있으면 건너뜀
+	OverwriteIfChanged           // 크기가 다르거나 객체가 더 최근이면 덮어씀
+)
+
+// DownloadPrefixOptions 는 DownloadPrefix 설정
+type DownloadPrefixOptions struct {
+	Concurrency int // 동시 다운로드 수, 기본 8
+	Overwrite   Overwrite
+}
+
+// DownloadResult 는 DownloadPrefix 의 객체별 결과
+type DownloadResult struct {
+	Key      string
+	Path     string // 로컬 파일 경로
+	Size     int64
+	Duration time.Duration
+	Skipped  bool // Overwrite 설정으로 받지 않음
+	Err      error
+}
+
+var ErrUnsafeKey = errors.New("key escapes target directory")
+
+// DownloadPrefix 는 prefix 아래 모든 객체를 localDir 아래 같은 상대 경로로 동시에 다운로드한다.
+// "/" 로 끝나는 디렉터리 표시 객체는 건너뛰고, localDir 밖을 가리키는 key("../" 등)는 ErrUnsafeKey 로 기록한다.
+// 받은 파일의 수정 시각은 객체의 LastModified 로 맞춰 OverwriteIfChanged 가 다음 실행에서 비교할 수 있게 한다.
+// 결과는 key 순으로 반환되고 객체별 실패는 Err 에 담긴다. 목록 조회에 실패하면 다운로드 전에 오류를 반환한다.
+func (s *Storage) DownloadPrefix(bucket, prefix, localDir string, options ...DownloadPrefixOptions) ([]DownloadResult, error) {
+	var opt DownloadPrefixOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 8
+	}
+
+	var (
+		results  []DownloadResult
+		modified = map[string]time.Time{}
+	)
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		if strings.HasSuffix(obj.Key, "/") {
+			return nil
+		}
+		results = append(results, prefixFile(localDir, prefix, obj))
+		modified[obj.Key] = obj.LastModified
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, opt.Concurrency)
+	)
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if _, err = s.WaitBlackout(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *DownloadResult, lastModified time.Time) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if opt.Overwrite.skip(result.Path, result.Size, lastModified) {
+				result.Skipped = true
+				return
+			}
+
+			start := time.Now()
+			result.Err = s.downloadFile(bucket, result.Key, result.Path, lastModified)
+			result.Duration = time.Since(start)
+		}(&results[i], modified[results[i].Key])
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// prefixFile 은 key 를 localDir 아래 경로로 바꾼다.
+func prefixFile(localDir, prefix string, obj ObjectInfo) DownloadResult {
+	result := DownloadResult{Key: obj.Key, Size: obj.Size}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(obj.Key, prefix), "/")
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		result.Err = fmt.Errorf("%w: %s", ErrUnsafeKey, obj.Key)
+		return result
+	}
+
+	result.Path = filepath.Join(localDir, filepath.FromSlash(rel))
+	return result
+}
+
+func (o Overwrite) skip(path string, size int64, lastModified time.Time) bool {
+	if o == OverwriteAlways {
+		return false
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if o == OverwriteNever {
+		return true
+	}
+	return stat.Size() == size && !lastModified.After(stat.ModTime())
+}
+
+func (s *Storage) downloadFile(bucket, key, path string, lastModified time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := s.Download(bucket, key, path); err != nil {
+		return err
+	}
+	if lastModified.IsZero() {
+		return nil
+	}
+	return os.Chtimes(path, lastModified, lastModified)
+}
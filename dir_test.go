@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "css", "vendor"), 0o755)
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>"), 0o644)
+	os.WriteFile(filepath.Join(dir, "css", "vendor", "a.css"), []byte("a{}"), 0o644)
+	os.Symlink(filepath.Join(dir, "index.html"), filepath.Join(dir, "link.html"))
+
+	files, err := dirFiles(dir, "site/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Key != "site/css/vendor/a.css" || files[1].Key != "site/index.html" || files[1].Size != 6 {
+		t.Errorf("%+v", files)
+	}
+
+	if _, err = dirFiles(filepath.Join(dir, "missing"), ""); err == nil {
+		t.Error("없는 디렉터리가 허용됨")
+	}
+}
+
+func TestPrefixFile(t *testing.T) {
+	dir := t.TempDir()
+
+	result := prefixFile(dir, "site/", ObjectInfo{Key: "site/css/a.css", Size: 3})
+	if result.Err != nil || result.Path != filepath.Join(dir, "css", "a.css") {
+		t.Errorf("%+v", result)
+	}
+
+	// prefix 가 "/" 로 끝나지 않아도 상대 경로로
+	if result = prefixFile(dir, "site", ObjectInfo{Key: "site/index.html"}); result.Path != filepath.Join(dir, "index.html") {
+		t.Errorf("%+v", result)
+	}
+
+	if result = prefixFile(dir, "site/", ObjectInfo{Key: "site/../../etc/passwd"}); !errors.Is(result.Err, ErrUnsafeKey) {
+		t.Errorf("%+v", result)
+	}
+}
+
+func TestOverwriteSkip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("abc"), 0o644)
+	stat, _ := os.Stat(path)
+	older, newer := stat.ModTime().Add(-time.Hour), stat.ModTime().Add(time.Hour)
+
+	cases := []struct {
+		overwrite    Overwrite
+		path         string
+		size         int64
+		lastModified time.Time
+		want         bool
+	}{
+		{OverwriteAlways, path, 3, older, false},
+		{OverwriteNever, path, 10, newer, true},
+		{OverwriteNever, path + ".missing", 3, older, false},
+		{OverwriteIfChanged, path, 3, older, true},
+		{OverwriteIfChanged, path, 4, older, false},
+		{OverwriteIfChanged, path, 3, newer, false},
+	}
+	for i, c := range cases {
+		if got := c.overwrite.skip(c.path, c.size, c.lastModified); got != c.want {
+			t.Errorf("%d: %v", i, got)
+		}
+	}
+}
+
+func TestUploadDirEmptyFile(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "empty.txt"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644)
+
+	results, err := store.UploadDir("bucket", dir, "site/", 2, Options{CacheControl: "no-cache"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("%s: %v", result.Key, result.Err)
+		}
+	}
+
+	object, ok := fake.objects["bucket/site/empty.txt"]
+	if !ok || len(object.data) != 0 || object.headers.Get("Cache-Control") != "no-cache" || object.headers.Get("Content-Type") != "text/plain" {
+		t.Fatalf("empty.txt: %+v", object)
+	}
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// 디렉터리(prefix)마다 저장되는 통계 객체 이름
+const dirStatsName = ".dirstats.json"
+
+// DirStats 는 prefix 바로 아래 객체들의 요약
+type DirStats struct {
+	Count     int64     `json:"count"`
+	Bytes     int64     `json:"bytes"`
+	NewestKey string    `json:"newest_key,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DirStats 는 prefix 의 통계 객체를 읽는다. 아직 없으면 빈 통계.
+// prefix 는 "photos/2024/" 처럼 "/" 로 끝나는 디렉터리 (루트는 "").
+func (s *Storage) DirStats(bucket, prefix string) (*DirStats, error) {
+	stats, _, err := s.readDirStats(bucket, dirStatsKey(prefix))
+	return stats, err
+}
+
+// RebuildDirStats 는 prefix 바로 아래 객체를 모두 나열해 통계 객체를 다시 만든다.
+// DirStats 를 켜기 전에 있던 객체나, 이 패키지를 거치지 않은 변경을 반영할 때 사용한다.
+func (s *Storage) RebuildDirStats(bucket, prefix string) (*DirStats, error) {
+	stats := &DirStats{}
+	var newest time.Time
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		name := strings.TrimPrefix(obj.Key, prefix)
+		if name == dirStatsName || strings.Contains(name, "/") {
+			return nil
+		}
+
+		stats.Count++
+		stats.Bytes += obj.Size
+		if obj.LastModified.After(newest) {
+			newest = obj.LastModified
+			stats.NewestKey = obj.Key
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.UpdatedAt = time.Now().UTC()
+	data, _ := json.Marshal(stats)
+	return stats, s.putBytes(bucket, dirStatsKey(prefix), data, "application/json")
+}
+
+func dirStatsKey(prefix string) string {
+	return prefix + dirStatsName
+}
+
+// dirOf key 가 속한 디렉터리 prefix, 예: "a/b/c.jpg" → "a/b/"
+func dirOf(key string) string {
+	dir := path.Dir(key)
+	if dir == "." {
+		return ""
+	}
+	return dir + "/"
+}
+
+// sizeBefore DirStats 를 켰을 때 덮어쓰기/삭제 전 객체 크기, 없거나 꺼져 있으면 -1
+func (s *Storage) sizeBefore(bucket, key string) int64 {
+	if !s.config.DirStats {
+		return -1
+	}
+
+	info, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return -1
+	}
+	return aws.ToInt64(info.ContentLength)
+}
+
+// updateDirStats 는 key 의 쓰기(size >= 0) 또는 삭제(size < 0)를 통계에 반영한다.
+// 통계는 부가 정보이므로 실패해도 원래 작업의 결과를 바꾸지 않는다.
+func (s *Storage) updateDirStats(bucket, key string, previous, size int64) {
+	if !s.config.DirStats || path.Base(key) == dirStatsName {
+		return
+	}
+
+	statsKey := dirStatsKey(dirOf(key))
+	conditional := s.Supports(CapConditionalWrite)
+
+	// 동시에 갱신하면 조건부 PUT 이 실패하므로 다시 읽어서 재시도
+	for attempt := 0; attempt < 5; attempt++ {
+		stats, etag, err := s.readDirStats(bucket, statsKey)
+		if err != nil {
+			return
+		}
+
+		if previous >= 0 {
+			stats.Count--
+			stats.Bytes -= previous
+		}
+		if size >= 0 {
+			stats.Count++
+			stats.Bytes += size
+			stats.NewestKey = key
+		}
+		stats.Count = max(stats.Count, 0)
+		stats.Bytes = max(stats.Bytes, 0)
+		stats.UpdatedAt = time.Now().UTC()
+
+		data, _ := json.Marshal(stats)
+		input := &s3.PutObjectInput{
+			Bucket:        aws.String(bucket),
+			Key:           aws.String(statsKey),
+			Body:          bytes.NewReader(data),
+			ContentLength: aws.Int64(int64(len(data))),
+			ContentType:   aws.String("application/json"),
+		}
+		if conditional {
+			if etag == "" {
+				input.IfNoneMatch = aws.String("*")
+			} else {
+				input.IfMatch = aws.String(etag)
+			}
+		}
+
+		_, err = s.client.PutObject(s.requestContext(), input)
+		if !isPreconditionFailed(err) {
+			return
+		}
+	}
+}
+
+// readDirStats 통계와 ETag, 없으면 빈 통계와 ""
+func (s *Storage) readDirStats(bucket, statsKey string) (*DirStats, string, error) {
+	output, err := s.getObject(bucket, statsKey)
+	if isNotFound(err) {
+		return &DirStats{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stats := &DirStats{}
+	if err = json.Unmarshal(data, stats); err != nil {
+		return nil, "", errors.New("invalid dir stats: " + statsKey)
+	}
+	return stats, aws.ToString(output.ETag), nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListHidesDirStats(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "photos/a.jpg", []byte("a"))
+	fake.put("bucket", "photos/"+dirStatsName, []byte("{}"))
+	fake.put("bucket", dirStatsName, []byte("{}"))
+
+	keys, _, err := store.List("bucket", "", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys, []string{"photos/a.jpg"}) {
+		t.Fatalf("got %v", keys)
+	}
+
+	var walked []string
+	err = store.Walk("bucket", "photos/", func(obj ObjectInfo) error {
+		walked = append(walked, obj.Key)
+		return nil
+	})
+	if err != nil || !slices.Equal(walked, []string{"photos/a.jpg"}) {
+		t.Fatalf("walk %v, %v", walked, err)
+	}
+}
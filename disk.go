@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+// InsufficientSpaceError 는 받을 파일보다 디스크 여유 공간이 작을 때 반환된다.
+// errors.Is(err, ErrInsufficientSpace) 도 true.
+type InsufficientSpaceError struct {
+	Path      string
+	Required  int64
+	Available int64
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("insufficient disk space for %s: need %d bytes, %d available", e.Path, e.Required, e.Available)
+}
+
+func (e *InsufficientSpaceError) Is(target error) bool {
+	return target == ErrInsufficientSpace
+}
+
+// preallocate 여유 공간을 확인하고 size 만큼 미리 할당한다. 크기를 모르면(-1) 아무것도 하지 않는다.
+func preallocate(fd *os.File, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	available, err := freeSpace(filepath.Dir(fd.Name()))
+	if err != nil {
+		return err
+	}
+	if available >= 0 && available < size {
+		return &InsufficientSpaceError{Path: fd.Name(), Required: size, Available: available}
+	}
+
+	return allocate(fd, size)
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// freeSpace 는 일반 사용자가 쓸 수 있는 여유 공간
+func freeSpace(dir string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
+
+// allocate 는 fallocate 로 실제 블록을 확보한다. 지원하지 않는 파일 시스템이면 크기만 늘린다.
+func allocate(fd *os.File, size int64) error {
+	err := syscall.Fallocate(int(fd.Fd()), 0, 0, size)
+	if errors.Is(err, syscall.ENOSPC) {
+		return &InsufficientSpaceError{Path: fd.Name(), Required: size}
+	}
+	if errors.Is(err, syscall.EOPNOTSUPP) {
+		return fd.Truncate(size)
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package storage
+
+import "os"
+
+// freeSpace 는 Linux 에서만 확인하며, 그 밖에는 알 수 없음(-1)
+func freeSpace(dir string) (int64, error) {
+	return -1, nil
+}
+
+// allocate 는 크기만 늘린다. 대부분의 파일 시스템에서 블록이 확보되지는 않는다(sparse).
+func allocate(fd *os.File, size int64) error {
+	return fd.Truncate(size)
+}
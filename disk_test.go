@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreallocate(t *testing.T) {
+	fd, err := os.Create(filepath.Join(t.TempDir(), "a.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+
+	if err = preallocate(fd, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err = preallocate(fd, 4096); err != nil {
+		t.Fatal(err)
+	}
+	if stat, _ := fd.Stat(); stat.Size() != 4096 {
+		t.Errorf("size %d", stat.Size())
+	}
+
+	if available, _ := freeSpace(filepath.Dir(fd.Name())); available > 0 {
+		err = preallocate(fd, available+1<<40)
+		var spaceErr *InsufficientSpaceError
+		if !errors.Is(err, ErrInsufficientSpace) || !errors.As(err, &spaceErr) || spaceErr.Available != available {
+			t.Errorf("%v", err)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// DownloadOption 은 Download, DownloadWriter 동작을 바꾼다.
+type DownloadOption interface {
+	applyDownload(*downloadOptions)
+}
+
+type downloadOptionFunc func(*downloadOptions)
+
+func (fn downloadOptionFunc) applyDownload(o *downloadOptions) {
+	fn(o)
+}
+
+type downloadOptions struct {
+	sha256   string
+	size     int64 // -1 이면 검사하지 않음
+	progress func(transferred, total int64)
+
+	partSize    int64
+	concurrency int
+
+	md5    string // WithVerifyChecksum, ETag 가 MD5 일 때
+	stored bool   // WithVerifyChecksum
+
+	preallocate bool // WithPreallocate, Download 만
+	fsync       bool // WithFsync, Download 만
+}
+
+// WithExpectedSHA256 받은 파일의 SHA-256(hex)이 다르면 *MismatchError
+func WithExpectedSHA256(sum string) DownloadOption {
+	return downloadOptionFunc(func(o *downloadOptions) {
+		o.sha256 = strings.ToLower(sum)
+	})
+}
+
+// WithExpectedSize 받은 파일의 크기가 다르면 *MismatchError
+func WithExpectedSize(size int64) DownloadOption {
+	return downloadOptionFunc(func(o *downloadOptions) {
+		o.size = size
+	})
+}
+
+// WithVerifyChecksum 은 받은 데이터를 객체에 저장된 값과 비교한다.
+// 업로드 시 저장한 SHA-256 checksum 이 있으면 그것을, 없으면 ETag(단일 PUT 의 MD5)와 크기를 사용한다.
+// multipart 로 올라가 ETag 가 MD5 가 아니면 크기만 확인한다. 다르면 ErrChecksumMismatch.
+// DownloadWriter 는 GET 응답의 ETag 와 크기만 사용한다.
+func WithVerifyChecksum() DownloadOption {
+	return downloadOptionFunc(func(o *downloadOptions) {
+		o.stored = true
+	})
+}
+
+// WithPreallocate 는 Download 전에 객체 크기만큼 디스크 여유 공간을 확인하고 파일을 미리 할당한다.
+// 공간이 부족하면 받기 전에 *InsufficientSpaceError 를 반환한다. 여유 공간 확인과 블록 확보는 Linux 에서만 한다.
+func WithPreallocate() DownloadOption {
+	return downloadOptionFunc(func(o *downloadOptions) {
+		o.preallocate = true
+	})
+}
+
+// WithFsync 는 Download 가 끝나면 fsync 로 파일을 디스크에 기록한 뒤 반환한다.
+func WithFsync() DownloadOption {
+	return downloadOptionFunc(func(o *downloadOptions) {
+		o.fsync = true
+	})
+}
+
+func newDownloadOptions(options []DownloadOption) *downloadOptions {
+	opt := &downloadOptions{size: -1}
+	for _, option := range options {
+		option.applyDownload(opt)
+	}
+	return opt
+}
+
+// MismatchError 는 받은 객체가 기대값과 다를 때 반환된다.
+// Field 가 "sha256", "md5" 이면 errors.Is(err, ErrChecksumMismatch) 도 true.
+type MismatchError struct {
+	Key      string
+	Field    string // "size", "sha256", "md5"
+	Expected string
+	Actual   string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch for %s: expected %s, got %s", e.Field, e.Key, e.Expected, e.Actual)
+}
+
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrChecksumMismatch && (e.Field == "sha256" || e.Field == "md5")
+}
+
+// DownloadWriter 는 객체를 임시 파일 없이 w 로 바로 복사한다.
+// HTTP 응답, pipe 등으로 중계할 때 사용하며 Download 와 같은 검증 옵션을 받는다.
+// 검증은 전송이 끝난 뒤에 하므로, 실패해도 이미 w 에 쓴 데이터는 되돌릴 수 없다.
+func (s *Storage) DownloadWriter(bucket, key string, w io.Writer, options ...DownloadOption) error {
+	s, b := s.withBudget()
+	return b.finish("DownloadWriter", s.downloadWriter(bucket, key, w, options...))
+}
+
+func (s *Storage) downloadWriter(bucket, key string, w io.Writer, options ...DownloadOption) error {
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return err
+	}
+	defer output.Body.Close()
+
+	opt := newDownloadOptions(options)
+	if opt.stored {
+		opt.expectStored(output.ETag, output.ContentLength, nil)
+	}
+	if opt.progress != nil {
+		w = &progressWriter{w: w, p: &progress{total: aws.ToInt64(output.ContentLength), fn: opt.progress}}
+	}
+
+	if !opt.needsVerify() {
+		_, err = io.Copy(w, output.Body)
+		return err
+	}
+
+	b := budgetFrom(s.requestContext())
+	d := opt.newDigest()
+	if _, err = io.Copy(io.MultiWriter(w, &verifyWriter{w: d, b: b}), output.Body); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = opt.verifyDigest(key, d)
+	b.addVerify(time.Since(start))
+	return err
+}
+
+var ErrObjectTooLarge = errors.New("object exceeds max size")
+
+// DownloadBytes 는 설정 파일, manifest 같은 작은 객체를 로컬 파일 없이 메모리로 읽는다.
+// maxSize(> 0)를 지정하면 그보다 큰 객체는 끝까지 읽지 않고 ErrObjectTooLarge 를 반환한다.
+func (s *Storage) DownloadBytes(bucket, key string, maxSize ...int64) ([]byte, error) {
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	if len(maxSize) == 0 || maxSize[0] <= 0 {
+		return io.ReadAll(output.Body)
+	}
+	return readLimited(key, output.Body, aws.ToInt64(output.ContentLength), maxSize[0])
+}
+
+// readLimited 는 limit byte 까지만 읽는다. Content-Length 가 없는(0) 응답도 limit 을 넘으면 멈춘다.
+func readLimited(key string, r io.Reader, length, limit int64) ([]byte, error) {
+	if length > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, max %d", ErrObjectTooLarge, key, length, limit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrObjectTooLarge, key, limit)
+	}
+	return data, nil
+}
+
+// DownloadRange 는 객체의 offset 부터 length byte 를 w 로 복사한다. length 가 0 이하이면 끝까지 복사한다.
+// 미디어 서버의 byte-range 응답이나 끊긴 다운로드를 이어 받을 때 사용한다.
+// offset 이 객체 크기 이상이면 provider 오류(InvalidRange)를 반환한다.
+func (s *Storage) DownloadRange(bucket, key string, offset, length int64, w io.Writer) error {
+	if offset < 0 {
+		return fmt.Errorf("invalid offset %d", offset)
+	}
+
+	output, err := s.getRange(bucket, key, offset, length)
+	if err != nil {
+		return err
+	}
+	defer output.Body.Close()
+
+	_, err = io.Copy(w, output.Body)
+	return err
+}
+
+// expectStored 객체에 저장된 값을 기대값으로 사용, 호출자가 지정한 값이 우선
+func (o *downloadOptions) expectStored(etag *string, size *int64, checksumSHA256 *string) {
+	if o.size < 0 && size != nil {
+		o.size = *size
+	}
+	if o.sha256 != "" {
+		return
+	}
+
+	// multipart 의 checksum 은 part checksum 의 checksum("<base64>-N")이라 비교할 수 없음
+	if sum, err := base64.StdEncoding.DecodeString(aws.ToString(checksumSHA256)); err == nil && len(sum) == sha256.Size {
+		o.sha256 = hex.EncodeToString(sum)
+		return
+	}
+
+	if tag := strings.ToLower(strings.Trim(aws.ToString(etag), `"`)); isMD5(tag) {
+		o.md5 = tag
+	}
+}
+
+func isMD5(etag string) bool {
+	if len(etag) != 2*md5.Size {
+		return false
+	}
+	_, err := hex.DecodeString(etag)
+	return err == nil
+}
+
+func (o *downloadOptions) needsVerify() bool {
+	return o.size >= 0 || o.sha256 != "" || o.md5 != ""
+}
+
+// digest 는 검증에 필요한 해시만 계산하는 writer
+type digest struct {
+	size   int64
+	sha256 hash.Hash
+	md5    hash.Hash
+}
+
+func (o *downloadOptions) newDigest() *digest {
+	d := &digest{}
+	if o.sha256 != "" {
+		d.sha256 = sha256.New()
+	}
+	if o.md5 != "" {
+		d.md5 = md5.New()
+	}
+	return d
+}
+
+func (d *digest) Write(b []byte) (int, error) {
+	d.size += int64(len(b))
+	if d.sha256 != nil {
+		d.sha256.Write(b)
+	}
+	if d.md5 != nil {
+		d.md5.Write(b)
+	}
+	return len(b), nil
+}
+
+func (o *downloadOptions) verifyDigest(key string, d *digest) error {
+	artifact := Artifact{Size: d.size}
+	if d.sha256 != nil {
+		artifact.SHA256 = hex.EncodeToString(d.sha256.Sum(nil))
+	}
+	if err := o.verify(key, artifact); err != nil {
+		return err
+	}
+
+	if d.md5 != nil {
+		if actual := hex.EncodeToString(d.md5.Sum(nil)); actual != o.md5 {
+			return &MismatchError{Key: key, Field: "md5", Expected: o.md5, Actual: actual}
+		}
+	}
+	return nil
+}
+
+// verifyFile 다운로드한 파일을 기대값과 비교
+func (o *downloadOptions) verifyFile(key, path string) error {
+	if !o.needsVerify() {
+		return nil
+	}
+
+	if o.sha256 == "" && o.md5 == "" {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return o.verify(key, Artifact{Size: stat.Size()})
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	d := o.newDigest()
+	if _, err = io.Copy(d, file); err != nil {
+		return err
+	}
+	return o.verifyDigest(key, d)
+}
+
+func (o *downloadOptions) verify(key string, artifact Artifact) error {
+	if o.size >= 0 && artifact.Size != o.size {
+		return &MismatchError{Key: key, Field: "size", Expected: fmt.Sprint(o.size), Actual: fmt.Sprint(artifact.Size)}
+	}
+	if o.sha256 != "" && artifact.SHA256 != o.sha256 {
+		return &MismatchError{Key: key, Field: "sha256", Expected: o.sha256, Actual: artifact.SHA256}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestDownloadVerify(t *testing.T) {
+	artifact := Artifact{Size: 3, SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"}
+
+	opt := newDownloadOptions([]DownloadOption{WithExpectedSize(3), WithExpectedSHA256("BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD")})
+	if err := opt.verify("abc.txt", artifact); err != nil {
+		t.Error(err)
+	}
+
+	err := newDownloadOptions([]DownloadOption{WithExpectedSHA256("00")}).verify("abc.txt", artifact)
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) || mismatch.Field != "sha256" || !errors.Is(err, ErrChecksumMismatch) {
+		t.Error("sha256 불일치 오류가 아님:", err)
+	}
+
+	err = newDownloadOptions([]DownloadOption{WithExpectedSize(4)}).verify("abc.txt", artifact)
+	if !errors.As(err, &mismatch) || mismatch.Field != "size" || errors.Is(err, ErrChecksumMismatch) {
+		t.Error("size 불일치 오류가 아님:", err)
+	}
+}
+
+func TestVerifyStored(t *testing.T) {
+	// "abc"
+	const (
+		md5Sum    = "900150983cd24fb0d6963f7d28e17f72"
+		sha256B64 = "ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0="
+	)
+
+	opt := newDownloadOptions([]DownloadOption{WithVerifyChecksum()})
+	opt.expectStored(aws.String(`"`+md5Sum+`"`), aws.Int64(3), aws.String(sha256B64))
+	if opt.size != 3 || opt.sha256 != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" || opt.md5 != "" {
+		t.Errorf("%+v", opt)
+	}
+
+	opt = newDownloadOptions([]DownloadOption{WithVerifyChecksum()})
+	opt.expectStored(aws.String(`"`+md5Sum+`"`), aws.Int64(3), aws.String(sha256B64+"-2"))
+	d := opt.newDigest()
+	d.Write([]byte("abc"))
+	if opt.md5 != md5Sum || opt.verifyDigest("abc.txt", d) != nil {
+		t.Errorf("%+v", opt)
+	}
+
+	d = opt.newDigest()
+	d.Write([]byte("abd"))
+	if err := opt.verifyDigest("abc.txt", d); !errors.Is(err, ErrChecksumMismatch) {
+		t.Error("md5 불일치 오류가 아님:", err)
+	}
+
+	// multipart ETag 는 크기만 확인
+	opt = newDownloadOptions([]DownloadOption{WithVerifyChecksum()})
+	opt.expectStored(aws.String(`"`+md5Sum+`-3"`), aws.Int64(3), nil)
+	if opt.md5 != "" || opt.sha256 != "" || opt.size != 3 {
+		t.Errorf("%+v", opt)
+	}
+}
+
+func TestByteRange(t *testing.T) {
+	if got := byteRange(100, 50); got != "bytes=100-149" {
+		t.Error(got)
+	}
+	if got := byteRange(100, 0); got != "bytes=100-" {
+		t.Error(got)
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	if data, err := readLimited("a.json", strings.NewReader("{}"), 2, 2); err != nil || string(data) != "{}" {
+		t.Error(string(data), err)
+	}
+	if _, err := readLimited("a.json", strings.NewReader("{}"), 2, 1); !errors.Is(err, ErrObjectTooLarge) {
+		t.Error("Content-Length 초과가 허용됨:", err)
+	}
+	if _, err := readLimited("a.json", strings.NewReader("{}"), 0, 1); !errors.Is(err, ErrObjectTooLarge) {
+		t.Error("본문 초과가 허용됨:", err)
+	}
+}
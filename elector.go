@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Elector 는 lease 객체를 조건부 PUT 으로 갱신하는 간단한 리더 선출기.
+// 참여자들의 시계가 TTL 에 비해 충분히 맞아 있다고 가정한다.
+// 조건부 쓰기(CapConditionalWrite)를 지원하는 스토리지에서만 동작한다.
+type Elector struct {
+	ID        string
+	TTL       time.Duration // default: 15s
+	OnElected func()
+	OnLost    func()
+
+	storage *Storage
+	bucket  string
+	key     string
+	etag    string
+	expires time.Time // 마지막으로 쓴 lease 의 만료 시각
+	leader  atomic.Bool
+}
+
+type lease struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+func (s *Storage) Elector(bucket, key, id string) *Elector {
+	return &Elector{
+		ID:      id,
+		TTL:     15 * time.Second,
+		storage: s,
+		bucket:  bucket,
+		key:     key,
+	}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run 은 ctx 가 끝날 때까지 TTL/3 간격으로 lease 획득/갱신을 시도한다.
+// 요청이 일시적으로 실패해도 마지막으로 쓴 lease 가 만료될 때까지는 리더를 유지한다.
+// 종료 시 리더였다면 lease 를 만료시켜 다른 참여자가 바로 가져갈 수 있게 한다.
+func (e *Elector) Run(ctx context.Context) error {
+	if e.TTL/3 <= 0 {
+		return fmt.Errorf("invalid elector TTL: %s", e.TTL)
+	}
+	if err := checkCapabilities(e.storage.Type(), []Capability{CapConditionalWrite}); err != nil {
+		return err
+	}
+	if err := e.storage.config.Policy.Allow(OpPut, e.key); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(e.TTL / 3)
+	defer ticker.Stop()
+
+	for {
+		e.transition(e.renew(ctx))
+
+		select {
+		case <-ctx.Done():
+			if e.IsLeader() {
+				e.release()
+				e.transition(false)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// renew 는 lease 획득/갱신을 한 번 시도하고 리더인지 반환한다.
+func (e *Elector) renew(ctx context.Context) bool {
+	leader, err := e.acquire(ctx)
+	if err != nil {
+		// 이미 가진 lease 는 만료 전까지 다른 참여자가 가져갈 수 없다
+		return e.IsLeader() && time.Now().Before(e.expires)
+	}
+	return leader
+}
+
+func (e *Elector) transition(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+
+	if leader && e.OnElected != nil {
+		e.OnElected()
+	}
+	if !leader && e.OnLost != nil {
+		e.OnLost()
+	}
+}
+
+func (e *Elector) acquire(ctx context.Context) (bool, error) {
+	output, err := e.storage.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(e.bucket),
+		Key:    aws.String(e.key),
+	})
+	if isNotFound(err) {
+		return e.put(ctx, "")
+	}
+	if err != nil {
+		return false, err
+	}
+
+	data, err := io.ReadAll(output.Body)
+	output.Body.Close()
+	if err != nil {
+		return false, err
+	}
+
+	var current lease
+	if err = json.Unmarshal(data, &current); err != nil {
+		return false, err
+	}
+
+	if current.Holder != e.ID && time.Now().Before(current.Expires) {
+		return false, nil
+	}
+
+	return e.put(ctx, aws.ToString(output.ETag))
+}
+
+// put etag 가 비어 있으면 lease 가 없을 때만, 있으면 etag 가 일치할 때만 쓴다.
+func (e *Elector) put(ctx context.Context, etag string) (bool, error) {
+	expires := time.Now().Add(e.TTL)
+	data, err := json.Marshal(lease{Holder: e.ID, Expires: expires})
+	if err != nil {
+		return false, err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(e.bucket),
+		Key:           aws.String(e.key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/json"),
+	}
+
+	if etag == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(etag)
+	}
+
+	output, err := e.storage.client.PutObject(ctx, input)
+	if isPreconditionFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	e.etag = aws.ToString(output.ETag)
+	e.expires = expires
+	return true, nil
+}
+
+func (e *Elector) release() {
+	data, _ := json.Marshal(lease{Holder: e.ID})
+	e.storage.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:        aws.String(e.bucket),
+		Key:           aws.String(e.key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/json"),
+		IfMatch:       aws.String(e.etag),
+	})
+}
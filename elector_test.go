@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestElectorAcquireRenewLose(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	ctx := context.Background()
+
+	var elected, lost atomic.Int32
+	a := store.Elector("bucket", "leader", "a")
+	a.OnElected = func() { elected.Add(1) }
+	a.OnLost = func() { lost.Add(1) }
+	b := store.Elector("bucket", "leader", "b")
+
+	// 획득
+	a.transition(a.renew(ctx))
+	if !a.IsLeader() || elected.Load() != 1 {
+		t.Fatal("a not elected")
+	}
+	if b.transition(b.renew(ctx)); b.IsLeader() {
+		t.Fatal("b elected while a holds the lease")
+	}
+
+	// 갱신
+	etag := a.etag
+	if a.transition(a.renew(ctx)); !a.IsLeader() || a.etag == etag {
+		t.Fatal("a not renewed")
+	}
+
+	// 일시적인 오류는 lease 가 유효한 동안 리더 유지
+	fake.fail = func(*http.Request) int { return http.StatusInternalServerError }
+	if a.transition(a.renew(ctx)); !a.IsLeader() || lost.Load() != 0 {
+		t.Fatal("a lost leadership on a transient error")
+	}
+	a.expires = time.Now().Add(-time.Second)
+	if a.transition(a.renew(ctx)); a.IsLeader() || lost.Load() != 1 {
+		t.Fatal("a kept leadership after its lease expired")
+	}
+	fake.fail = nil
+
+	// 다른 참여자가 가져간 lease
+	a.transition(a.renew(ctx))
+	data, _ := json.Marshal(lease{Holder: "b", Expires: time.Now().Add(time.Minute)})
+	fake.put("bucket", "leader", data)
+	if a.transition(a.renew(ctx)); a.IsLeader() || lost.Load() != 2 {
+		t.Fatal("a kept leadership after b took the lease")
+	}
+}
+
+func TestElectorRunRelease(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e := store.Elector("bucket", "leader", "a")
+	e.OnElected = cancel
+
+	if err := e.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if e.IsLeader() {
+		t.Fatal("still leader after Run returned")
+	}
+
+	// 종료 시 lease 를 만료시켜 다른 참여자가 바로 가져갈 수 있음
+	var current lease
+	if err := json.Unmarshal(fake.get("bucket", "leader"), &current); err != nil || time.Now().Before(current.Expires) {
+		t.Fatalf("lease not released: %+v, %v", current, err)
+	}
+}
+
+func TestElectorRunInvalid(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+	e := store.Elector("bucket", "leader", "a")
+	e.TTL = 2
+	if err := e.Run(context.Background()); err == nil {
+		t.Error("expected TTL error")
+	}
+
+	b2, _ := newFakeStorage(t, Config{Endpoint: "https://s3.us-west-004.backblazeb2.com"})
+	var capability *CapabilityError
+	if err := b2.Elector("bucket", "leader", "a").Run(context.Background()); !errors.As(err, &capability) {
+		t.Errorf("expected CapabilityError, got %v", err)
+	}
+}
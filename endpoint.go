@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	awsMiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyendpoints "github.com/aws/smithy-go/endpoints"
+)
+
+// EndpointRule 은 Operations 에 해당하는 요청을 Endpoint 로 보낸다.
+// 예: 읽기(OpGet, OpInfo)는 가까운 지역의 mirror / 가속 endpoint 로, 쓰기는 기본 Endpoint 로.
+type EndpointRule struct {
+	Operations []Operation // OpGet, OpInfo, OpList, OpPut, OpDelete, OpBucket
+	Endpoint   string      // scheme 이 없으면 https
+}
+
+// endpointResolver 는 작업별로 SDK 의 endpoint 를 바꾼다. presigned URL 도 같은 규칙을 따른다.
+type endpointResolver struct {
+	rules []EndpointRule
+	next  s3.EndpointResolverV2
+}
+
+func newEndpointResolver(rules []EndpointRule) *endpointResolver {
+	rules = slices.Clone(rules)
+	for i, rule := range rules {
+		if !strings.Contains(rule.Endpoint, "://") {
+			rules[i].Endpoint = "https://" + rule.Endpoint
+		}
+	}
+	return &endpointResolver{rules: rules, next: s3.NewDefaultEndpointResolverV2()}
+}
+
+func (r *endpointResolver) ResolveEndpoint(ctx context.Context, params s3.EndpointParameters) (smithyendpoints.Endpoint, error) {
+	if endpoint := r.find(operationOf(awsMiddleware.GetOperationName(ctx))); endpoint != "" {
+		params.Endpoint = &endpoint
+	}
+	return r.next.ResolveEndpoint(ctx, params)
+}
+
+// find 는 op 에 맞는 첫 규칙의 endpoint, 없으면 ""
+func (r *endpointResolver) find(op Operation) string {
+	for _, rule := range r.rules {
+		if slices.Contains(rule.Operations, op) {
+			return rule.Endpoint
+		}
+	}
+	return ""
+}
+
+// operationOf 는 SDK 작업 이름을 Policy 의 작업 단위로 분류한다.
+func operationOf(name string) Operation {
+	switch {
+	case name == "GetObject":
+		return OpGet
+	case name == "HeadObject":
+		return OpInfo
+	case strings.HasPrefix(name, "List"):
+		return OpList
+	case strings.HasPrefix(name, "Delete") && strings.Contains(name, "Bucket"):
+		return OpBucket
+	case strings.HasPrefix(name, "Delete"), name == "AbortMultipartUpload":
+		return OpDelete
+	case strings.Contains(name, "Bucket"):
+		return OpBucket
+	default:
+		return OpPut // PutObject, CopyObject, multipart
+	}
+}
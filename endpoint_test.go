@@ -0,0 +1,45 @@
+package storage
+
+import "testing"
+
+func TestOperationOf(t *testing.T) {
+	cases := map[string]Operation{
+		"GetObject":               OpGet,
+		"HeadObject":              OpInfo,
+		"ListObjectsV2":           OpList,
+		"ListParts":               OpList,
+		"PutObject":               OpPut,
+		"UploadPart":              OpPut,
+		"CompleteMultipartUpload": OpPut,
+		"CopyObject":              OpPut,
+		"DeleteObject":            OpDelete,
+		"DeleteObjects":           OpDelete,
+		"AbortMultipartUpload":    OpDelete,
+		"DeleteBucket":            OpBucket,
+		"DeleteBucketPolicy":      OpBucket,
+		"HeadBucket":              OpBucket,
+		"PutBucketPolicy":         OpBucket,
+	}
+	for name, want := range cases {
+		if got := operationOf(name); got != want {
+			t.Errorf("%s: %s", name, got)
+		}
+	}
+}
+
+func TestEndpointResolverFind(t *testing.T) {
+	r := newEndpointResolver([]EndpointRule{
+		{Operations: []Operation{OpGet, OpInfo}, Endpoint: "mirror.example.com"},
+		{Operations: []Operation{OpGet, OpList}, Endpoint: "http://other:9000"},
+	})
+
+	if got := r.find(OpGet); got != "https://mirror.example.com" {
+		t.Error(got)
+	}
+	if got := r.find(OpList); got != "http://other:9000" {
+		t.Error(got)
+	}
+	if got := r.find(OpPut); got != "" {
+		t.Error(got)
+	}
+}
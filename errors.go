@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	awsMiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// provider 와 관계없이 errors.Is 로 비교할 수 있는 오류
+var (
+	ErrNotFound       = errors.New("object not found")
+	ErrBucketNotFound = errors.New("bucket not found")
+	ErrAccessDenied   = errors.New("access denied")
+)
+
+// Error 는 S3 요청 오류를 분류한 것. 원래 SDK 오류는 Unwrap 으로 꺼낼 수 있다.
+type Error struct {
+	Operation  string // 예: HeadObject
+	Code       string // provider 오류 코드, 예: NoSuchKey
+	StatusCode int
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Operation, e.Err)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBucketNotFound:
+		return e.Code == "NoSuchBucket"
+	case ErrNotFound:
+		return e.Code == "NoSuchKey" || e.Code == "NotFound" ||
+			(e.StatusCode == http.StatusNotFound && e.Code != "NoSuchBucket")
+	case ErrAccessDenied:
+		return e.Code == "AccessDenied" || e.Code == "Forbidden" || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// addErrorMiddleware 는 SDK 오류를 *Error 로 감싼다.
+func addErrorMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("StorageError", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		if err == nil {
+			return out, metadata, nil
+		}
+
+		var storageErr *Error
+		if errors.As(err, &storageErr) {
+			return out, metadata, err
+		}
+
+		wrapped := &Error{Operation: awsMiddleware.GetOperationName(ctx), Code: errorCode(err), Err: err}
+		var respErr interface{ HTTPStatusCode() int }
+		if errors.As(err, &respErr) {
+			wrapped.StatusCode = respErr.HTTPStatusCode()
+		}
+		return out, metadata, wrapped
+	}), middleware.After)
+}
+
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+// isPreconditionFailed 조건부 요청(If-Match / If-None-Match) 실패 여부
+func isPreconditionFailed(err error) bool {
+	code := errorCode(err)
+	return code == "PreconditionFailed" || code == "ConditionalRequestConflict"
+}
+
+// isNotFound NoSuchKey / NotFound(HEAD) / 404
+func isNotFound(err error) bool {
+	switch errorCode(err) {
+	case "NoSuchKey", "NotFound":
+		return true
+	}
+
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,30 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestErrorClassification(t *testing.T) {
+	cases := []struct {
+		err    *storage.Error
+		target error
+	}{
+		{&storage.Error{Code: "NoSuchKey", StatusCode: 404}, storage.ErrNotFound},
+		{&storage.Error{StatusCode: 404}, storage.ErrNotFound}, // HEAD 는 본문이 없음
+		{&storage.Error{Code: "NoSuchBucket", StatusCode: 404}, storage.ErrBucketNotFound},
+		{&storage.Error{Code: "AccessDenied", StatusCode: 403}, storage.ErrAccessDenied},
+	}
+
+	for _, c := range cases {
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("%+v 는 %v 이어야 함", c.err, c.target)
+		}
+	}
+
+	if errors.Is(&storage.Error{Code: "NoSuchBucket", StatusCode: 404}, storage.ErrNotFound) {
+		t.Error("NoSuchBucket 은 ErrNotFound 가 아님")
+	}
+}
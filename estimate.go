@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TransferEstimate 는 디렉터리 전송 전 예상치
+type TransferEstimate struct {
+	Files    int64
+	Bytes    int64
+	Duration time.Duration // measured 처리량 기준, 측정값이 없으면 0
+}
+
+// EstimateTransfer 는 source 의 파일 수와 전체 크기를 세고 measured(Benchmark 결과)로 예상 시간을 계산한다.
+// source 가 로컬 디렉터리이면 업로드 처리량을, 아니면 "bucket/prefix" 로 보고 다운로드 처리량을 사용한다.
+// 작은 파일이 많으면 처리량보다 요청당 지연이 더 오래 걸리므로 둘 중 큰 값을 사용한다.
+func (s *Storage) EstimateTransfer(source string, measured BenchmarkResult) (TransferEstimate, error) {
+	var estimate TransferEstimate
+
+	if stat, err := os.Stat(source); err == nil && stat.IsDir() {
+		err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			estimate.Files++
+			estimate.Bytes += info.Size()
+			return nil
+		})
+		if err != nil {
+			return estimate, err
+		}
+
+		estimate.Duration = estimateDuration(estimate, measured.Upload, measured.Concurrency)
+		return estimate, nil
+	}
+
+	bucket, prefix, _ := strings.Cut(source, "/")
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		estimate.Files++
+		estimate.Bytes += obj.Size
+		return nil
+	})
+	if err != nil {
+		return estimate, err
+	}
+
+	estimate.Duration = estimateDuration(estimate, measured.Download, measured.Concurrency)
+	return estimate, nil
+}
+
+// estimateDuration max(전체 크기 / 처리량, 파일 수 × 평균 지연 / 동시성)
+func estimateDuration(estimate TransferEstimate, m Measurement, concurrency int) time.Duration {
+	if m.Throughput <= 0 {
+		return 0
+	}
+
+	byThroughput := time.Duration(float64(estimate.Bytes) / m.Throughput * float64(time.Second))
+	byLatency := m.Latency * time.Duration(estimate.Files) / time.Duration(max(concurrency, 1))
+	return max(byThroughput, byLatency)
+}
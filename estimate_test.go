@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateDuration(t *testing.T) {
+	m := Measurement{Throughput: 100 << 20, Latency: 100 * time.Millisecond}
+
+	// 큰 파일: 처리량 기준
+	if got := estimateDuration(TransferEstimate{Files: 10, Bytes: 10 << 30}, m, 4); got != 102400*time.Millisecond {
+		t.Error(got)
+	}
+
+	// 작은 파일이 많으면 지연 기준
+	if got := estimateDuration(TransferEstimate{Files: 10000, Bytes: 10 << 20}, m, 4); got != 250*time.Second {
+		t.Error(got)
+	}
+
+	if got := estimateDuration(TransferEstimate{Files: 1, Bytes: 1}, Measurement{}, 1); got != 0 {
+		t.Error("측정값이 없으면 0:", got)
+	}
+}
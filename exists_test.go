@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestExists(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "a.txt", []byte("a"))
+
+	if ok, err := store.Exists("bucket", "a.txt"); err != nil || !ok {
+		t.Errorf("a.txt: %v, %v", ok, err)
+	}
+	if ok, err := store.Exists("bucket", "missing.txt"); err != nil || ok {
+		t.Errorf("missing.txt: %v, %v", ok, err)
+	}
+
+	// 404 가 아닌 오류는 false 로 숨기지 않는다
+	fake.fail = func(r *http.Request) int { return http.StatusForbidden }
+	if ok, err := store.Exists("bucket", "a.txt"); !errors.Is(err, ErrAccessDenied) || ok {
+		t.Errorf("403: %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,599 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeS3 는 테스트용 메모리 S3 서버 (path-style, 조건부 PUT, multipart, DeleteObjects 지원)
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]*fakeObject // bucket + "/" + key
+	uploads map[string]*fakeUpload
+	nextID  int
+
+	bucketConfigs map[string][]byte // bucket + "?" + 하위 리소스(notification, policy 등) → PUT 한 본문
+
+	// fail 이 0 이 아닌 상태 코드를 반환하면 요청을 그 상태로 실패시킨다.
+	fail func(r *http.Request) int
+	// deleteError 가 true 인 key 는 DeleteObjects 결과에 오류로 담긴다.
+	deleteError func(key string) bool
+}
+
+type fakeObject struct {
+	data     []byte
+	etag     string
+	modified time.Time
+	headers  http.Header // Content-Type, Cache-Control 등
+	metadata map[string]string
+	acl      string // private, public-read
+}
+
+type fakeUpload struct {
+	bucket, key string
+	headers     http.Header
+	metadata    map[string]string
+	acl         string
+	parts       map[int][]byte
+	initiated   time.Time
+}
+
+// newFakeStorage 는 fakeS3 에 연결된 R2 Storage 를 만든다.
+func newFakeStorage(t *testing.T, config Config) (*Storage, *fakeS3) {
+	t.Helper()
+
+	// 환경의 CA bundle 은 사용자 Transport 와 함께 쓸 수 없음
+	t.Setenv("AWS_CA_BUNDLE", "")
+
+	fake := &fakeS3{objects: map[string]*fakeObject{}, uploads: map[string]*fakeUpload{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+
+	if config.Endpoint == "" {
+		config.Endpoint = "https://account.r2.cloudflarestorage.com"
+	}
+	config.AccessKeyID, config.SecretAccessKey = "test", "test"
+	config.MaxAttempts = 1
+	config.Transport = fakeTransport(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	config.S3Options = append(config.S3Options, func(o *s3.Options) {
+		o.UsePathStyle = true
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+
+	store, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, fake
+}
+
+type fakeTransport func(*http.Request) (*http.Response, error)
+
+func (fn fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+// put 은 요청을 거치지 않고 객체를 만든다.
+func (f *fakeS3) put(bucket, key string, data []byte, modified ...time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object := &fakeObject{data: data, etag: md5Hex(data), modified: time.Now().UTC(), headers: http.Header{}}
+	if len(modified) > 0 {
+		object.modified = modified[0]
+	}
+	f.objects[bucket+"/"+key] = object
+}
+
+// get 은 객체 내용, 없으면 nil
+func (f *fakeS3) get(bucket, key string) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if object, ok := f.objects[bucket+"/"+key]; ok {
+		return object.data
+	}
+	return nil
+}
+
+// keys 는 bucket 의 모든 key (정렬)
+func (f *fakeS3) keys(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for name := range f.objects {
+		if b, key, _ := strings.Cut(name, "/"); b == bucket {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	if f.fail != nil {
+		if status := f.fail(r); status != 0 {
+			fakeError(w, status, "InternalError")
+			return
+		}
+	}
+
+	if r.Header.Get("X-Amz-Decoded-Content-Length") != "" {
+		var ok bool
+		if body, ok = decodeAWSChunked(body); !ok {
+			fakeError(w, http.StatusBadRequest, "BadDigest")
+			return
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+	name := bucket + "/" + key
+
+	switch {
+	case key == "" && r.Method == http.MethodGet && query.Get("list-type") == "2":
+		f.list(w, bucket, query)
+	case key == "" && r.Method == http.MethodPost && query.Has("delete"):
+		f.deleteObjects(w, bucket, body)
+	case key == "" && r.Method == http.MethodGet && query.Has("uploads"):
+		f.listUploads(w, bucket)
+	case key == "" && query.Has("notification"):
+		f.bucketConfig(w, r, bucket+"?notification", body, "")
+	case key == "" && query.Has("policy"):
+		f.bucketConfig(w, r, bucket+"?policy", body, "NoSuchBucketPolicy")
+	case key == "" && query.Has("publicAccessBlock"):
+		f.bucketConfig(w, r, bucket+"?publicAccessBlock", body, "NoSuchPublicAccessBlockConfiguration")
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		f.nextID++
+		id := strconv.Itoa(f.nextID)
+		f.uploads[id] = &fakeUpload{bucket: bucket, key: key, headers: objectHeadersOf(r), metadata: metadataOf(r), acl: aclOf(r), parts: map[int][]byte{}, initiated: time.Now().UTC()}
+		writeXML(w, fmt.Sprintf("<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, id))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		f.complete(w, name, query.Get("uploadId"), body)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		f.uploadPart(w, r, query, body)
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		f.listParts(w, query.Get("uploadId"))
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		f.copyObject(w, r, name)
+	case r.Method == http.MethodPut:
+		f.putObject(w, r, name, body)
+	case r.Method == http.MethodGet && query.Has("acl"):
+		f.objectACL(w, name)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		f.getObject(w, r, name)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) putObject(w http.ResponseWriter, r *http.Request, name string, body []byte) {
+	current, exists := f.objects[name]
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		fakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && (!exists || strings.Trim(match, `"`) != current.etag) {
+		fakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	object := &fakeObject{data: body, etag: md5Hex(body), modified: time.Now().UTC(), headers: objectHeadersOf(r), metadata: metadataOf(r), acl: aclOf(r)}
+	f.objects[name] = object
+	w.Header().Set("ETag", `"`+object.etag+`"`)
+}
+
+func (f *fakeS3) getObject(w http.ResponseWriter, r *http.Request, name string) {
+	object, ok := f.objects[name]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && strings.Trim(match, `"`) != object.etag {
+		fakeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return
+	}
+
+	for name, values := range object.headers {
+		w.Header()[name] = values
+	}
+	for name, value := range object.metadata {
+		w.Header().Set("X-Amz-Meta-"+name, value)
+	}
+	w.Header().Set("ETag", `"`+object.etag+`"`)
+	w.Header().Set("Last-Modified", object.modified.Format(http.TimeFormat))
+
+	data := object.data
+	status := http.StatusOK
+	if spec := r.Header.Get("Range"); spec != "" {
+		var start, end int64 = 0, int64(len(data)) - 1
+		from, to, _ := strings.Cut(strings.TrimPrefix(spec, "bytes="), "-")
+		start, _ = strconv.ParseInt(from, 10, 64)
+		if to != "" {
+			end, _ = strconv.ParseInt(to, 10, 64)
+		}
+		end = min(end, int64(len(data))-1)
+		if start > end {
+			fakeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		data = data[start : end+1]
+		status = http.StatusPartialContent
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+func (f *fakeS3) copyObject(w http.ResponseWriter, r *http.Request, name string) {
+	source, ok := f.objects[copySourceName(r)]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	object := *source
+	object.modified = time.Now().UTC()
+	object.acl = aclOf(r) // AWS 처럼 복사본의 ACL 은 요청 헤더로 정해진다
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		object.headers, object.metadata = objectHeadersOf(r), metadataOf(r)
+	}
+	f.objects[name] = &object
+	writeXML(w, fmt.Sprintf(`<CopyObjectResult><ETag>"%s"</ETag></CopyObjectResult>`, object.etag))
+}
+
+func (f *fakeS3) uploadPart(w http.ResponseWriter, r *http.Request, query url.Values, body []byte) {
+	upload, ok := f.uploads[query.Get("uploadId")]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	number, _ := strconv.Atoi(query.Get("partNumber"))
+
+	if r.Header.Get("X-Amz-Copy-Source") == "" {
+		upload.parts[number] = body
+		w.Header().Set("ETag", `"`+md5Hex(body)+`"`)
+		return
+	}
+
+	source, ok := f.objects[copySourceName(r)]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	var start, end int64
+	fmt.Sscanf(r.Header.Get("X-Amz-Copy-Source-Range"), "bytes=%d-%d", &start, &end)
+	upload.parts[number] = source.data[start : end+1]
+	writeXML(w, fmt.Sprintf(`<CopyPartResult><ETag>"%s"</ETag></CopyPartResult>`, md5Hex(upload.parts[number])))
+}
+
+func (f *fakeS3) listUploads(w http.ResponseWriter, bucket string) {
+	var out strings.Builder
+	out.WriteString("<ListMultipartUploadsResult><IsTruncated>false</IsTruncated>")
+	for _, id := range slices.Sorted(maps.Keys(f.uploads)) {
+		upload := f.uploads[id]
+		if upload.bucket != bucket {
+			continue
+		}
+		fmt.Fprintf(&out, "<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>%s</Initiated></Upload>",
+			xmlEscape(upload.key), id, upload.initiated.Format(time.RFC3339))
+	}
+	out.WriteString("</ListMultipartUploadsResult>")
+	writeXML(w, out.String())
+}
+
+func (f *fakeS3) listParts(w http.ResponseWriter, id string) {
+	upload, ok := f.uploads[id]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+
+	var out strings.Builder
+	out.WriteString("<ListPartsResult><IsTruncated>false</IsTruncated>")
+	for _, number := range slices.Sorted(maps.Keys(upload.parts)) {
+		data := upload.parts[number]
+		fmt.Fprintf(&out, `<Part><PartNumber>%d</PartNumber><ETag>"%s"</ETag><Size>%d</Size></Part>`, number, md5Hex(data), len(data))
+	}
+	out.WriteString("</ListPartsResult>")
+	writeXML(w, out.String())
+}
+
+func (f *fakeS3) complete(w http.ResponseWriter, name, id string, body []byte) {
+	upload, ok := f.uploads[id]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+
+	var request struct {
+		Parts []struct {
+			PartNumber int
+		} `xml:"Part"`
+	}
+	xml.Unmarshal(body, &request)
+
+	var (
+		data  []byte
+		etags []byte
+	)
+	for _, part := range request.Parts {
+		chunk, ok := upload.parts[part.PartNumber]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		data = append(data, chunk...)
+		sum := md5.Sum(chunk)
+		etags = append(etags, sum[:]...)
+	}
+
+	etag := fmt.Sprintf("%s-%d", md5Hex(etags), len(request.Parts))
+	f.objects[name] = &fakeObject{data: data, etag: etag, modified: time.Now().UTC(), headers: upload.headers, metadata: upload.metadata, acl: upload.acl}
+	delete(f.uploads, id)
+	writeXML(w, fmt.Sprintf(`<CompleteMultipartUploadResult><ETag>"%s"</ETag></CompleteMultipartUploadResult>`, etag))
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, bucket string, query url.Values) {
+	prefix := query.Get("prefix")
+	after := max(query.Get("start-after"), query.Get("continuation-token"))
+	limit, _ := strconv.Atoi(query.Get("max-keys"))
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+
+	var keys []string
+	for name := range f.objects {
+		if b, key, _ := strings.Cut(name, "/"); b == bucket && strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var out strings.Builder
+	out.WriteString("<ListBucketResult>")
+	truncated := len(keys) > limit
+	if truncated {
+		keys = keys[:limit]
+		fmt.Fprintf(&out, "<NextContinuationToken>%s</NextContinuationToken>", xmlEscape(keys[len(keys)-1]))
+	}
+	fmt.Fprintf(&out, "<IsTruncated>%t</IsTruncated><KeyCount>%d</KeyCount>", truncated, len(keys))
+	for _, key := range keys {
+		object := f.objects[bucket+"/"+key]
+		fmt.Fprintf(&out, `<Contents><Key>%s</Key><Size>%d</Size><ETag>"%s"</ETag><LastModified>%s</LastModified></Contents>`,
+			xmlEscape(key), len(object.data), object.etag, object.modified.Format(time.RFC3339))
+	}
+	out.WriteString("</ListBucketResult>")
+	writeXML(w, out.String())
+}
+
+func (f *fakeS3) deleteObjects(w http.ResponseWriter, bucket string, body []byte) {
+	var request struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	xml.Unmarshal(body, &request)
+
+	var out strings.Builder
+	out.WriteString("<DeleteResult>")
+	for _, object := range request.Objects {
+		if f.deleteError != nil && f.deleteError(object.Key) {
+			fmt.Fprintf(&out, "<Error><Key>%s</Key><Code>AccessDenied</Code><Message>denied</Message></Error>", xmlEscape(object.Key))
+			continue
+		}
+		delete(f.objects, bucket+"/"+object.Key)
+	}
+	out.WriteString("</DeleteResult>")
+	writeXML(w, out.String())
+}
+
+// objectACL 은 소유자 FULL_CONTROL 과, public-read 이면 AllUsers READ 를 반환한다.
+func (f *fakeS3) objectACL(w http.ResponseWriter, name string) {
+	object, ok := f.objects[name]
+	if !ok {
+		fakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	grants := `<Grant><Grantee xsi:type="CanonicalUser"><ID>owner</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>`
+	if object.acl == "public-read" {
+		grants += `<Grant><Grantee xsi:type="Group"><URI>` + allUsersURI + `</URI></Grantee><Permission>READ</Permission></Grant>`
+	}
+	writeXML(w, `<AccessControlPolicy><Owner><ID>owner</ID></Owner><AccessControlList>`+grants+`</AccessControlList></AccessControlPolicy>`)
+}
+
+const allUsersURI = "http://acs.amazonaws.com/groups/global/AllUsers"
+
+// aclOf 는 x-amz-acl 또는 AllUsers 에 대한 x-amz-grant-read 를 public-read 로 본다.
+func aclOf(r *http.Request) string {
+	if acl := r.Header.Get("X-Amz-Acl"); acl != "" {
+		return acl
+	}
+	if strings.Contains(r.Header.Get("X-Amz-Grant-Read"), allUsersURI) {
+		return "public-read"
+	}
+	return "private"
+}
+
+// bucketConfig 는 PUT 한 버킷 설정을 그대로 저장했다가 GET 에 돌려준다.
+// 설정이 없으면 missingCode 로 404, missingCode 가 "" 이면 빈 NotificationConfiguration.
+func (f *fakeS3) bucketConfig(w http.ResponseWriter, r *http.Request, name string, body []byte, missingCode string) {
+	switch r.Method {
+	case http.MethodPut:
+		if f.bucketConfigs == nil {
+			f.bucketConfigs = map[string][]byte{}
+		}
+		f.bucketConfigs[name] = body
+	case http.MethodDelete:
+		delete(f.bucketConfigs, name)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		if body, ok := f.bucketConfigs[name]; ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write(body)
+			return
+		}
+		if missingCode != "" {
+			fakeError(w, http.StatusNotFound, missingCode)
+			return
+		}
+		writeXML(w, "<NotificationConfiguration></NotificationConfiguration>")
+	default:
+		fakeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// decodeAWSChunked 는 aws-chunked 본문을 풀고 trailer 의 SHA-1 / SHA-256 체크섬을 확인한다.
+func decodeAWSChunked(body []byte) ([]byte, bool) {
+	var data []byte
+	for {
+		line, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, false
+		}
+		sizeHex, _, _ := strings.Cut(string(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || int64(len(rest)) < size {
+			return nil, false
+		}
+		if size == 0 {
+			body = rest
+			break
+		}
+		data = append(data, rest[:size]...)
+		body = bytes.TrimPrefix(rest[size:], []byte("\r\n"))
+	}
+
+	for line := range strings.SplitSeq(string(body), "\r\n") {
+		name, value, _ := strings.Cut(line, ":")
+		var sum []byte
+		switch name {
+		case "x-amz-checksum-sha1":
+			digest := sha1.Sum(data)
+			sum = digest[:]
+		case "x-amz-checksum-sha256":
+			digest := sha256.Sum256(data)
+			sum = digest[:]
+		default:
+			continue
+		}
+		if value != base64.StdEncoding.EncodeToString(sum) {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+func copySourceName(r *http.Request) string {
+	source, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	return strings.TrimPrefix(source, "/")
+}
+
+func objectHeadersOf(r *http.Request) http.Header {
+	headers := http.Header{}
+	for _, name := range []string{"Content-Type", "Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Language"} {
+		if value := r.Header.Get(name); value != "" {
+			headers.Set(name, value)
+		}
+	}
+	return headers
+}
+
+func metadataOf(r *http.Request) map[string]string {
+	metadata := map[string]string{}
+	for name := range r.Header {
+		if meta, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+			metadata[meta] = r.Header.Get(name)
+		}
+	}
+	return metadata
+}
+
+func writeXML(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>`+body)
+}
+
+func fakeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func TestFakeS3(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	if err := store.putBytes("bucket", "a/b.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.getBytes("bucket", "a/b.txt")
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+
+	keys, _, err := store.List("bucket", "a/", 10)
+	if err != nil || !slices.Equal(keys, []string{"a/b.txt"}) {
+		t.Fatalf("list %v, %v", keys, err)
+	}
+
+	if _, err = store.getBytes("bucket", "missing"); !isNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	if err = store.Delete("bucket", "a/b.txt"); err != nil || fake.get("bucket", "a/b.txt") != nil {
+		t.Fatalf("delete: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	awsMiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+)
+
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultInjector 는 staging 에서 스토리지 장애 대응을 확인하기 위해
+// 지정한 작업/key 에 확률적으로 오류, 지연, 본문 잘림을 주입한다.
+type FaultInjector struct {
+	Rules []FaultRule
+}
+
+type FaultRule struct {
+	Operations  []string // API 작업 이름 (예: "GetObject"), 비어 있으면 전체
+	KeyPrefix   string
+	Probability float64 // 0 ~ 1
+	Latency     time.Duration
+	Truncate    int64 // GetObject 본문을 이 바이트 수 이후 끊음
+	Err         error // Latency, Truncate 가 없으면 default: ErrInjectedFault
+}
+
+func (f *FaultInjector) addMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("StorageFaultInjector", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		operation := awsMiddleware.GetOperationName(ctx)
+		key := inputKey(in.Parameters)
+
+		var truncate int64
+		for _, rule := range f.Rules {
+			if !rule.match(operation, key) || rand.Float64() >= rule.Probability {
+				continue
+			}
+
+			if rule.Latency > 0 {
+				select {
+				case <-time.After(rule.Latency):
+				case <-ctx.Done():
+					return middleware.InitializeOutput{}, middleware.Metadata{}, ctx.Err()
+				}
+			}
+
+			if rule.Err != nil {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, rule.Err
+			}
+			if rule.Latency == 0 && rule.Truncate == 0 {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, ErrInjectedFault
+			}
+
+			if rule.Truncate > 0 {
+				truncate = rule.Truncate
+			}
+		}
+
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		if output, ok := out.Result.(*s3.GetObjectOutput); ok && truncate > 0 {
+			output.Body = &truncatedBody{ReadCloser: output.Body, remain: truncate}
+		}
+		return out, metadata, err
+	}), middleware.After)
+}
+
+func (r *FaultRule) match(operation, key string) bool {
+	if len(r.Operations) > 0 && !slices.Contains(r.Operations, operation) {
+		return false
+	}
+	return strings.HasPrefix(key, r.KeyPrefix)
+}
+
+// inputKey SDK 입력 구조체의 Key 필드
+func inputKey(params any) string {
+	v := reflect.ValueOf(params)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := v.Elem().FieldByName("Key")
+	if !field.IsValid() {
+		return ""
+	}
+
+	if key, ok := field.Interface().(*string); ok && key != nil {
+		return *key
+	}
+	return ""
+}
+
+// truncatedBody remain 바이트 이후 연결이 끊긴 것처럼 동작
+type truncatedBody struct {
+	io.ReadCloser
+	remain int64
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remain <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	if int64(len(p)) > b.remain {
+		p = p[:b.remain]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remain -= int64(n)
+	return n, err
+}
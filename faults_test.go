@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestFaultInjectorError(t *testing.T) {
+	errFault := errors.New("disk on fire")
+	store, fake := newFakeStorage(t, Config{Faults: &FaultInjector{Rules: []FaultRule{
+		{Operations: []string{"HeadObject"}, KeyPrefix: "fail/", Probability: 1, Err: errFault},
+		{Operations: []string{"PutObject"}, KeyPrefix: "fail/", Probability: 1},
+	}}})
+	fake.put("bucket", "fail/a", []byte("a"))
+	fake.put("bucket", "ok/a", []byte("a"))
+
+	var served atomic.Int64
+	fake.fail = func(*http.Request) int {
+		served.Add(1)
+		return 0
+	}
+
+	if _, err := store.Info("bucket", "fail/a"); !errors.Is(err, errFault) {
+		t.Errorf("HeadObject fail/: %v", err)
+	}
+	if err := store.putBytes("bucket", "fail/b", []byte("b"), "text/plain"); !errors.Is(err, ErrInjectedFault) {
+		t.Errorf("PutObject fail/: %v", err)
+	}
+	if served.Load() != 0 {
+		t.Errorf("주입된 요청 %d 개가 서버에 도달함", served.Load())
+	}
+
+	// 다른 key, 다른 작업에는 주입하지 않는다
+	if _, err := store.Info("bucket", "ok/a"); err != nil {
+		t.Errorf("HeadObject ok/: %v", err)
+	}
+	if _, err := store.getBytes("bucket", "fail/a"); err != nil {
+		t.Errorf("GetObject fail/: %v", err)
+	}
+	if served.Load() != 2 {
+		t.Errorf("served = %d", served.Load())
+	}
+}
+
+func TestFaultInjectorLatency(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Faults: &FaultInjector{Rules: []FaultRule{
+		{Operations: []string{"HeadObject"}, KeyPrefix: "slow/", Probability: 1, Latency: 100 * time.Millisecond},
+	}}})
+	fake.put("bucket", "slow/a", []byte("a"))
+	fake.put("bucket", "fast/a", []byte("a"))
+
+	start := time.Now()
+	if _, err := store.Info("bucket", "slow/a"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("slow/ elapsed %v", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := store.Info("bucket", "fast/a"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("fast/ elapsed %v", elapsed)
+	}
+}
+
+func TestFaultInjectorTruncate(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Faults: &FaultInjector{Rules: []FaultRule{
+		{Operations: []string{"GetObject"}, KeyPrefix: "cut/", Probability: 1, Truncate: 4},
+	}}})
+	fake.put("bucket", "cut/a", []byte("0123456789"))
+	fake.put("bucket", "full/a", []byte("0123456789"))
+
+	read := func(key string) ([]byte, error) {
+		output, err := store.client.GetObject(store.requestContext(), &s3.GetObjectInput{Bucket: aws.String("bucket"), Key: aws.String(key)})
+		if err != nil {
+			return nil, err
+		}
+		defer output.Body.Close()
+		return io.ReadAll(output.Body)
+	}
+
+	data, err := read("cut/a")
+	if !errors.Is(err, io.ErrUnexpectedEOF) || string(data) != "0123" {
+		t.Errorf("cut/: %q, %v", data, err)
+	}
+	data, err = read("full/a")
+	if err != nil || string(data) != "0123456789" {
+		t.Errorf("full/: %q, %v", data, err)
+	}
+}
+
+func TestFaultInjectorProbability(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Faults: &FaultInjector{Rules: []FaultRule{
+		{Operations: []string{"HeadObject"}, Probability: 0.3},
+	}}})
+	fake.put("bucket", "a", []byte("a"))
+
+	const total = 500
+	var injected int
+	for range total {
+		_, err := store.Info("bucket", "a")
+		switch {
+		case errors.Is(err, ErrInjectedFault):
+			injected++
+		case err != nil:
+			t.Fatal(err)
+		}
+	}
+	// 0.3 * 500 = 150, 표준편차 약 10
+	if injected < 100 || injected > 200 {
+		t.Errorf("injected %d / %d", injected, total)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	deleteManifestPrefix = ".delete-manifests/"
+	deleteManifestTTL    = 24 * time.Hour
+)
+
+var ErrManifestExpired = errors.New("delete manifest expired")
+
+// ConfirmError 는 GuardedDelete 모드의 DeletePrefix 가 반환한다.
+// 삭제 목록을 확인한 뒤 Confirm(ManifestID) 를 호출해야 실제로 삭제된다.
+type ConfirmError struct {
+	ManifestID string
+	Count      int
+}
+
+func (e *ConfirmError) Error() string {
+	return fmt.Sprintf("%d objects pending deletion, confirm manifest %s", e.Count, e.ManifestID)
+}
+
+// deleteManifest 는 확인 대기 중인 삭제 목록
+type deleteManifest struct {
+	Bucket    string    `json:"bucket"`
+	Prefix    string    `json:"prefix"`
+	Keys      []string  `json:"keys"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// planDelete 삭제될 key 목록을 <bucket>/.delete-manifests/ 에 저장
+// manifest 는 목록에 나타나지 않으므로 prefix 가 .delete-manifests/ 를 포함해도 삭제 대상이 아니다.
+func (s *Storage) planDelete(bucket, prefix string) ([]string, error) {
+	keys, err := s.DeletePrefix(bucket, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	return keys, s.saveDeletePlan(bucket, prefix, keys)
+}
+
+// saveDeletePlan 은 keys 를 manifest 로 저장하고 *ConfirmError 를 반환한다.
+func (s *Storage) saveDeletePlan(bucket, prefix string, keys []string) error {
+	random := make([]byte, 8)
+	rand.Read(random)
+	now := time.Now().UTC()
+	name := now.Format("20060102T150405Z") + "-" + hex.EncodeToString(random)
+
+	data, _ := json.Marshal(deleteManifest{
+		Bucket:    bucket,
+		Prefix:    prefix,
+		Keys:      keys,
+		CreatedAt: now,
+		ExpiresAt: now.Add(deleteManifestTTL),
+	})
+	if err := s.putBytes(bucket, deleteManifestPrefix+name+".json", data, "application/json"); err != nil {
+		return err
+	}
+
+	// bucket 이름에는 "/" 가 없으므로 ID 만으로 manifest 위치를 알 수 있다
+	return &ConfirmError{ManifestID: bucket + "/" + name, Count: len(keys)}
+}
+
+// Confirm 은 DeletePrefix 가 저장한 삭제 목록을 실행하고 삭제한 key 목록을 반환한다.
+// 목록을 만든 뒤 prefix 에 새로 추가된 객체는 삭제하지 않는다.
+// manifest 는 24시간 뒤 만료되며, 실행이 끝나면 삭제된다.
+func (s *Storage) Confirm(manifestID string) ([]string, error) {
+	bucket, name, ok := strings.Cut(manifestID, "/")
+	if !ok || bucket == "" || name == "" {
+		return nil, fmt.Errorf("invalid manifest id: %s", manifestID)
+	}
+	manifestKey := deleteManifestPrefix + name + ".json"
+
+	data, err := s.getBytes(bucket, manifestKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest deleteManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	if time.Now().After(manifest.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s", ErrManifestExpired, manifestID)
+	}
+
+	failed, err := s.DeleteMany(bucket, manifest.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]string, 0, len(manifest.Keys))
+	for _, key := range manifest.Keys {
+		if _, ok := failed[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+
+	for key, e := range failed {
+		return deleted, fmt.Errorf("%d objects not deleted, %s: %w", len(failed), key, e)
+	}
+
+	return deleted, s.Delete(bucket, manifestKey)
+}
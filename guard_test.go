@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGuardedDeletePrefix(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{GuardedDelete: true})
+	fake.put("bucket", ".cache/a", []byte("a"))
+	fake.put("bucket", ".cache/b", []byte("b"))
+	fake.put("bucket", "keep", []byte("k"))
+
+	keys, err := store.DeletePrefix("bucket", ".")
+	var confirm *ConfirmError
+	if !errors.As(err, &confirm) || confirm.Count != 2 {
+		t.Fatalf("expected ConfirmError for 2 objects, got %v", err)
+	}
+	if !slices.Equal(keys, []string{".cache/a", ".cache/b"}) {
+		t.Fatalf("planned %v", keys)
+	}
+	if fake.get("bucket", ".cache/a") == nil {
+		t.Fatal("deleted before confirm")
+	}
+
+	// 두 번째 계획은 첫 manifest 를 삭제 대상에 넣지 않는다
+	keys, _ = store.DeletePrefix("bucket", ".")
+	if len(keys) != 2 {
+		t.Fatalf("manifest planned for deletion: %v", keys)
+	}
+
+	deleted, err := store.Confirm(confirm.ManifestID)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("confirm %v, %v", deleted, err)
+	}
+	if fake.get("bucket", ".cache/a") != nil || fake.get("bucket", "keep") == nil {
+		t.Fatalf("remaining %v", fake.keys("bucket"))
+	}
+
+	// 실행한 manifest 는 삭제됨
+	if _, err = store.Confirm(confirm.ManifestID); !isNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestConfirmExpired(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{GuardedDelete: true})
+	fake.put("bucket", "a", []byte("a"))
+
+	data, _ := json.Marshal(deleteManifest{Bucket: "bucket", Keys: []string{"a"}, ExpiresAt: time.Now().Add(-time.Minute)})
+	fake.put("bucket", deleteManifestPrefix+"old.json", data)
+
+	if _, err := store.Confirm("bucket/old"); !errors.Is(err, ErrManifestExpired) {
+		t.Fatalf("expected ErrManifestExpired, got %v", err)
+	}
+	if fake.get("bucket", "a") == nil {
+		t.Fatal("expired manifest deleted objects")
+	}
+}
+
+func TestConfirmPartialFailure(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{GuardedDelete: true})
+	fake.put("bucket", "logs/a", []byte("a"))
+	fake.put("bucket", "logs/b", []byte("b"))
+	fake.deleteError = func(key string) bool { return key == "logs/b" }
+
+	_, err := store.DeletePrefix("bucket", "logs/")
+	var confirm *ConfirmError
+	if !errors.As(err, &confirm) {
+		t.Fatalf("expected ConfirmError, got %v", err)
+	}
+
+	deleted, err := store.Confirm(confirm.ManifestID)
+	if err == nil || !slices.Equal(deleted, []string{"logs/a"}) {
+		t.Fatalf("got %v, %v", deleted, err)
+	}
+
+	// 실패하면 manifest 를 남겨 다시 실행할 수 있다
+	fake.deleteError = nil
+	if deleted, err = store.Confirm(confirm.ManifestID); err != nil || len(deleted) != 2 {
+		t.Fatalf("retry %v, %v", deleted, err)
+	}
+	if len(fake.keys("bucket")) != 0 {
+		t.Fatalf("remaining %v", fake.keys("bucket"))
+	}
+}
+
+func TestConfirmInvalidID(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+	for _, id := range []string{"", "bucket", "/name", "bucket/"} {
+		if _, err := store.Confirm(id); err == nil {
+			t.Errorf("%q: expected error", id)
+		}
+	}
+}
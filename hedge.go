@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Hedge 는 읽기 요청(GET/HEAD)이 Delay 안에 응답하지 않으면 같은 요청을 한 번 더 보내고
+// 먼저 성공한 응답을 사용한다. 추가 요청은 전체 요청의 MaxRatio 를 넘지 않는다.
+type Hedge struct {
+	Delay    time.Duration
+	MaxRatio float64 // default: 0.05
+
+	requests atomic.Int64
+	hedged   atomic.Int64
+}
+
+type hedgeResult[T any] struct {
+	value T
+	err   error
+	index int
+}
+
+// Stats 는 누적 요청 수와 그 중 추가로 보낸 요청 수
+func (h *Hedge) Stats() (requests, hedged int64) {
+	return h.requests.Load(), h.hedged.Load()
+}
+
+func (h *Hedge) allow() bool {
+	ratio := h.MaxRatio
+	if ratio <= 0 {
+		ratio = 0.05
+	}
+
+	if float64(h.hedged.Load()+1) > ratio*float64(h.requests.Load()) {
+		return false
+	}
+	h.hedged.Add(1)
+	return true
+}
+
+// cancelBody 는 Close 할 때 요청 context 를 함께 정리하는 Body
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// hedged 는 fn 을 실행하고 필요하면 한 번 더 실행한다.
+// 늦게 도착한 성공 응답은 discard 로 정리한다 (예: Body 닫기).
+// 승자의 context 는 bind 가 있으면 응답에 넘겨(예: Body 를 닫을 때 취소) 정리하고, 없으면 바로 취소한다.
+func hedged[T any](parent context.Context, h *Hedge, fn func(ctx context.Context) (T, error), discard func(T), bind func(T, context.CancelFunc)) (T, error) {
+	if h == nil || h.Delay <= 0 {
+		return fn(parent)
+	}
+	h.requests.Add(1)
+
+	var (
+		results = make(chan hedgeResult[T], 2)
+		cancels []context.CancelFunc
+	)
+
+	start := func() {
+		ctx, cancel := context.WithCancel(parent)
+		cancels = append(cancels, cancel)
+
+		index := len(cancels) - 1
+		go func() {
+			value, err := fn(ctx)
+			results <- hedgeResult[T]{value: value, err: err, index: index}
+		}()
+	}
+
+	start()
+	timer := time.NewTimer(h.Delay)
+	defer timer.Stop()
+
+	var (
+		pending = 1
+		last    hedgeResult[T]
+	)
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 && h.allow() {
+				start()
+				pending++
+			}
+		case last = <-results:
+			pending--
+			if last.err != nil {
+				continue
+			}
+
+			// 승자는 Body 를 계속 읽어야 하므로 나머지 요청만 취소
+			for i, cancel := range cancels {
+				if i != last.index {
+					cancel()
+				}
+			}
+			if bind != nil {
+				bind(last.value, cancels[last.index])
+			} else {
+				cancels[last.index]()
+			}
+			if pending > 0 {
+				go func() {
+					if late := <-results; late.err == nil && discard != nil {
+						discard(late.value)
+					}
+				}()
+			}
+			return last.value, nil
+		}
+	}
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return last.value, last.err
+}
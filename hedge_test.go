@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedged(t *testing.T) {
+	h := &Hedge{Delay: 10 * time.Millisecond, MaxRatio: 1}
+
+	var calls atomic.Int32
+	value, err := hedged(context.Background(), h, func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			// 첫 요청은 취소될 때까지 응답하지 않음
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return 2, nil
+	}, nil, nil)
+
+	if err != nil || value != 2 {
+		t.Fatal("두 번째 요청 결과를 받지 못함:", value, err)
+	}
+
+	if requests, extra := h.Stats(); requests != 1 || extra != 1 {
+		t.Error("통계가 잘못됨:", requests, extra)
+	}
+
+	// 비율 상한을 넘으면 추가 요청을 보내지 않음
+	h = &Hedge{Delay: time.Millisecond, MaxRatio: 0.01}
+	_, err = hedged(context.Background(), h, func(ctx context.Context) (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return 0, errors.New("slow")
+	}, nil, nil)
+
+	if _, extra := h.Stats(); err == nil || extra != 0 {
+		t.Error("상한을 넘어 추가 요청함:", extra)
+	}
+}
+
+func TestHedgedWinnerContext(t *testing.T) {
+	h := &Hedge{Delay: time.Millisecond, MaxRatio: 1}
+	slow := func(ctx context.Context) (context.Context, error) {
+		time.Sleep(5 * time.Millisecond)
+		return ctx, nil
+	}
+
+	// bind 가 없으면 승자 context 도 바로 정리
+	ctx, err := hedged(context.Background(), h, slow, nil, nil)
+	if err != nil || ctx.Err() == nil {
+		t.Fatal("winner context not cancelled:", err)
+	}
+
+	// Body 를 닫을 때 정리
+	var body io.ReadCloser
+	ctx, err = hedged(context.Background(), h, slow, nil, func(ctx context.Context, cancel context.CancelFunc) {
+		body = &cancelBody{ReadCloser: io.NopCloser(strings.NewReader("")), cancel: cancel}
+	})
+	if err != nil || ctx.Err() != nil {
+		t.Fatal("winner context cancelled before Close:", err)
+	}
+	body.Close()
+	if ctx.Err() == nil {
+		t.Fatal("winner context not cancelled on Close")
+	}
+}
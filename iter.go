@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Objects 는 prefix 아래 모든 객체를 순회하는 iterator 를 반환한다.
+// 요청은 ctx 로 보내며, 목록 조회가 실패하면 오류를 한 번 yield 하고 끝난다.
+//
+//	for obj, err := range store.Objects(ctx, "bucket", "images/") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(obj.Key)
+//	}
+func (s *Storage) Objects(ctx context.Context, bucket, prefix string) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		var (
+			c     = s.WithContext(ctx)
+			buf   = make([]ObjectInfo, 0, 1000)
+			token []string
+			next  string
+			err   error
+		)
+
+		for {
+			buf, next, err = c.ListInto(buf, bucket, prefix, 1000, token...)
+			if err != nil {
+				yield(ObjectInfo{}, err)
+				return
+			}
+
+			for _, obj := range buf {
+				if !yield(obj, nil) {
+					return
+				}
+			}
+
+			if next == "" {
+				return
+			}
+			if err = ctx.Err(); err != nil {
+				yield(ObjectInfo{}, err)
+				return
+			}
+			token = []string{next}
+		}
+	}
+}
+
+// ListIter 는 Objects 와 같지만 WithContext 의 ctx 를 사용하고, limit(> 0)을 지정하면 최대 limit 개까지만 순회한다.
+func (s *Storage) ListIter(bucket, prefix string, limit ...int) iter.Seq2[ObjectInfo, error] {
+	return func(yield func(ObjectInfo, error) bool) {
+		count := 0
+		for obj, err := range s.Objects(s.requestContext(), bucket, prefix) {
+			if !yield(obj, err) {
+				return
+			}
+			count++
+			if len(limit) > 0 && limit[0] > 0 && count >= limit[0] {
+				return
+			}
+		}
+	}
+}
+
+// Keys 는 Objects 의 key 만 순회한다.
+func (s *Storage) Keys(ctx context.Context, bucket, prefix string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for obj, err := range s.Objects(ctx, bucket, prefix) {
+			if !yield(obj.Key, err) {
+				return
+			}
+		}
+	}
+}
+
+// ObjectVersion 은 버전 관리 버킷의 객체 버전 또는 삭제 표시(delete marker)
+type ObjectVersion struct {
+	Key          string    `json:"key"`
+	VersionID    string    `json:"version_id"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"` // 따옴표 제거
+	LastModified time.Time `json:"last_modified"`
+	IsLatest     bool      `json:"is_latest"`
+	DeleteMarker bool      `json:"delete_marker"`
+}
+
+// Versions 는 prefix 아래 객체의 버전 이력을 key 순, 같은 key 는 최신 버전부터 순회한다.
+// 버전 관리를 지원하지 않는 스토리지에서는 provider 오류를 yield 한다.
+func (s *Storage) Versions(ctx context.Context, bucket, prefix string) iter.Seq2[ObjectVersion, error] {
+	return func(yield func(ObjectVersion, error) bool) {
+		if err := s.config.Policy.Allow(OpList, prefix); err != nil {
+			yield(ObjectVersion{}, err)
+			return
+		}
+
+		var keyMarker, versionIDMarker *string
+		for {
+			output, err := s.client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+				Bucket:          aws.String(bucket),
+				Prefix:          aws.String(prefix),
+				KeyMarker:       keyMarker,
+				VersionIdMarker: versionIDMarker,
+			})
+			if err != nil {
+				yield(ObjectVersion{}, err)
+				return
+			}
+
+			versions := make([]ObjectVersion, 0, len(output.Versions)+len(output.DeleteMarkers))
+			for _, v := range output.Versions {
+				versions = append(versions, ObjectVersion{
+					Key:          aws.ToString(v.Key),
+					VersionID:    aws.ToString(v.VersionId),
+					Size:         aws.ToInt64(v.Size),
+					ETag:         strings.Trim(aws.ToString(v.ETag), `"`),
+					LastModified: aws.ToTime(v.LastModified),
+					IsLatest:     aws.ToBool(v.IsLatest),
+				})
+			}
+			for _, m := range output.DeleteMarkers {
+				versions = append(versions, ObjectVersion{
+					Key:          aws.ToString(m.Key),
+					VersionID:    aws.ToString(m.VersionId),
+					LastModified: aws.ToTime(m.LastModified),
+					IsLatest:     aws.ToBool(m.IsLatest),
+					DeleteMarker: true,
+				})
+			}
+			sortVersions(versions)
+
+			for _, v := range versions {
+				if !yield(v, nil) {
+					return
+				}
+			}
+
+			if !aws.ToBool(output.IsTruncated) {
+				return
+			}
+			keyMarker = output.NextKeyMarker
+			versionIDMarker = output.NextVersionIdMarker
+		}
+	}
+}
+
+// sortVersions 버전과 삭제 표시는 따로 오므로 key 순, 최신 순으로 합친다.
+func sortVersions(versions []ObjectVersion) {
+	slices.SortStableFunc(versions, func(a, b ObjectVersion) int {
+		if c := cmp.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return b.LastModified.Compare(a.LastModified)
+	})
+}
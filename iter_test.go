@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortVersions(t *testing.T) {
+	now := time.Now()
+	versions := []ObjectVersion{
+		{Key: "b", VersionID: "b1", LastModified: now.Add(-time.Hour)},
+		{Key: "a", VersionID: "a1", LastModified: now.Add(-time.Hour)},
+		{Key: "a", VersionID: "a2", LastModified: now, IsLatest: true},
+		{Key: "a", VersionID: "a3", LastModified: now.Add(-time.Minute), DeleteMarker: true},
+	}
+	sortVersions(versions)
+
+	want := []string{"a2", "a3", "a1", "b1"}
+	for i, v := range versions {
+		if v.VersionID != want[i] {
+			t.Fatalf("%d: got %s, want %s", i, v.VersionID, want[i])
+		}
+	}
+}
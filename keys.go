@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidKey = errors.New("invalid key")
+
+const (
+	maxKeyLength     = 1024 // bytes, S3 / R2 / B2 공통
+	maxSegmentLength = 250  // bytes, B2: "/" 사이 한 구간
+)
+
+// ValidateKey 는 네트워크 요청 전에 현재 스토리지의 key 규칙을 검사한다.
+func (s *Storage) ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("%w: empty", ErrInvalidKey)
+	case !utf8.ValidString(key):
+		return fmt.Errorf("%w: not valid UTF-8", ErrInvalidKey)
+	case len(key) > maxKeyLength:
+		return fmt.Errorf("%w: %d bytes exceeds %d", ErrInvalidKey, len(key), maxKeyLength)
+	case strings.ContainsFunc(key, func(r rune) bool { return r < 0x20 || r == 0x7f }):
+		// S3 는 허용하지만 XML 목록 응답에서 깨진다
+		return fmt.Errorf("%w: control character", ErrInvalidKey)
+	}
+
+	if s.Type() == B2 {
+		if strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") || strings.Contains(key, "//") {
+			return fmt.Errorf("%w: b2 does not allow leading, trailing or double slash", ErrInvalidKey)
+		}
+
+		for _, segment := range strings.Split(key, "/") {
+			if len(segment) > maxSegmentLength {
+				return fmt.Errorf("%w: b2 path segment exceeds %d bytes", ErrInvalidKey, maxSegmentLength)
+			}
+		}
+	}
+
+	return nil
+}
+
+// prepareKey 는 Config.TruncateKeys 에 따라 key 를 줄이고, 검사와 Policy 확인을 한다.
+func (s *Storage) prepareKey(op Operation, key string) (string, error) {
+	if s.config.TruncateKeys {
+		key = truncateKey(key, maxKeyLength)
+	}
+
+	if err := s.ValidateKey(key); err != nil {
+		return key, err
+	}
+
+	return key, s.config.Policy.Allow(op, key)
+}
+
+// truncateKey 는 긴 key 를 "<앞부분>-<sha256 16자리><확장자>" 로 줄인다.
+// 원래 key 전체의 해시를 쓰므로 같은 key 는 항상 같은 결과가 된다.
+func truncateKey(key string, limit int) string {
+	if len(key) <= limit {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	suffix := "-" + hex.EncodeToString(sum[:8]) + path.Ext(key)
+	if len(suffix) > limit/2 {
+		suffix = "-" + hex.EncodeToString(sum[:8])
+	}
+
+	head := key[:limit-len(suffix)]
+	for !utf8.ValidString(head) {
+		head = head[:len(head)-1]
+	}
+
+	return head + suffix
+}
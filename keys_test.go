@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateKey(t *testing.T) {
+	b2 := &Storage{config: Config{Endpoint: "https://s3.us-west-004.backblazeb2.com"}}
+	r2 := &Storage{config: Config{Endpoint: "https://id.r2.cloudflarestorage.com"}}
+
+	invalid := []string{"", "a\x00b", strings.Repeat("a", 1025), string([]byte{0xff})}
+	for _, key := range invalid {
+		if err := r2.ValidateKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%q 가 허용됨", key)
+		}
+	}
+
+	if err := r2.ValidateKey("a//b"); err != nil {
+		t.Error("r2 에서 거부됨:", err)
+	}
+	if err := b2.ValidateKey("a//b"); !errors.Is(err, ErrInvalidKey) {
+		t.Error("b2 에서 허용됨")
+	}
+	if err := b2.ValidateKey(strings.Repeat("a", 251) + "/b"); !errors.Is(err, ErrInvalidKey) {
+		t.Error("b2 구간 길이 제한이 동작하지 않음")
+	}
+}
+
+func TestTruncateKey(t *testing.T) {
+	key := "videos/" + strings.Repeat("가", 400) + ".mp4"
+
+	short := truncateKey(key, maxKeyLength)
+	if len(short) > maxKeyLength || !utf8.ValidString(short) || !strings.HasSuffix(short, ".mp4") {
+		t.Fatal("잘못 줄어듦:", len(short), short[len(short)-30:])
+	}
+
+	if truncateKey(key, maxKeyLength) != short {
+		t.Error("같은 key 의 결과가 다름")
+	}
+	if truncateKey(key+"x", maxKeyLength) == short {
+		t.Error("다른 key 가 같은 결과")
+	}
+	if truncateKey("a.txt", maxKeyLength) != "a.txt" {
+		t.Error("짧은 key 가 바뀜")
+	}
+}
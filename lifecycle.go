@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"time"
+)
+
+// 객체 메타데이터 키 (x-amz-meta-*)
+const (
+	metaCreatedBy = "created-by"
+	metaExpiresAt = "expires-at" // RFC3339
+)
+
+func (s *Storage) lifecycleMetadata(opt *Options) map[string]string {
+	if s.config.CreatedBy == "" && opt.ExpiresAt.IsZero() {
+		return nil
+	}
+
+	metadata := map[string]string{}
+	if s.config.CreatedBy != "" {
+		metadata[metaCreatedBy] = s.config.CreatedBy
+	}
+	if !opt.ExpiresAt.IsZero() {
+		metadata[metaExpiresAt] = opt.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return metadata
+}
+
+// SweepExpired 는 prefix 아래에서 expires-at 메타데이터가 지난 객체를 삭제한다.
+// 객체 단위 만료를 지원하지 않는 스토리지에서 TTL 을 흉내 내기 위한 것으로, 주기적으로 실행한다.
+// 목록에는 메타데이터가 없으므로 객체마다 HEAD 요청을 보낸다. 반환값은 삭제한 객체 수.
+// Config.Blackouts 시간대에는 멈췄다가 이어서 진행한다.
+func (s *Storage) SweepExpired(bucket, prefix string) (int, error) {
+	var (
+		deleted int
+		now     = time.Now()
+	)
+
+	err := s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		if _, err := s.WaitBlackout(); err != nil {
+			return err
+		}
+
+		info, err := s.Info(bucket, obj.Key)
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		value, ok := info.Metadata[metaExpiresAt]
+		if !ok {
+			return nil
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, value)
+		if err != nil || expiresAt.After(now) {
+			return nil
+		}
+
+		if err = s.Delete(bucket, obj.Key); err != nil {
+			return err
+		}
+		deleted++
+		return nil
+	})
+
+	return deleted, err
+}
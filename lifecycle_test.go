@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSweepExpired(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{CreatedBy: "test"})
+
+	now := time.Now()
+	for key, expires := range map[string]time.Time{
+		"tmp/expired": now.Add(-time.Minute),
+		"tmp/future":  now.Add(time.Hour),
+		"other/old":   now.Add(-time.Hour),
+	} {
+		path := filepath.Join(t.TempDir(), "file")
+		os.WriteFile(path, []byte(key), 0o644)
+		if err := store.Upload("bucket", key, path, Options{ExpiresAt: expires}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.put("bucket", "tmp/forever", []byte("x"))
+	fake.put("bucket", "tmp/invalid", []byte("x"))
+	fake.objects["bucket/tmp/invalid"].metadata = map[string]string{metaExpiresAt: "tomorrow"}
+
+	if got := fake.objects["bucket/tmp/future"].metadata; got[metaExpiresAt] == "" || got[metaCreatedBy] != "test" {
+		t.Fatalf("metadata %v", got)
+	}
+
+	deleted, err := store.SweepExpired("bucket", "tmp/")
+	if err != nil || deleted != 1 {
+		t.Fatalf("deleted %d, %v", deleted, err)
+	}
+	want := []string{"other/old", "tmp/forever", "tmp/future", "tmp/invalid"}
+	if got := fake.keys("bucket"); !slices.Equal(got, want) {
+		t.Errorf("remaining %v", got)
+	}
+}
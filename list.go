@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ObjectInfo 는 SDK 타입에 의존하지 않는 객체 정보
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"` // 따옴표 제거
+	LastModified time.Time `json:"last_modified"`
+	StorageClass string    `json:"storage_class,omitempty"`
+
+	// InfoObject 만 채움, 목록 결과에는 없음 (ListObjectsV2 는 메타데이터를 반환하지 않음)
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Note        string            `json:"note,omitempty"` // SetNote 메모, 디코딩됨
+}
+
+// ListInto 는 List 와 같지만 결과를 ObjectInfo 로 변환해 buf[:0] 에 이어 붙여 반환한다.
+// 재사용되는 것은 결과 slice 뿐이며, SDK 응답은 페이지마다 새로 디코딩한다.
+func (s *Storage) ListInto(buf []ObjectInfo, bucket, prefix string, length int, token ...string) ([]ObjectInfo, string, error) {
+	buf = buf[:0]
+
+	output, err := s.listObjects(bucket, prefix, listOptions(length, token))
+	if err != nil {
+		return buf, "", err
+	}
+
+	return appendObjectInfos(buf, output.Contents), aws.ToString(output.NextContinuationToken), nil
+}
+
+// appendObjectInfos 는 cap 이 충분하면 할당 없이 buf 에 이어 붙인다 (key 등 문자열은 응답의 것을 공유).
+func appendObjectInfos(buf []ObjectInfo, contents []types.Object) []ObjectInfo {
+	for i := range contents {
+		buf = append(buf, objectInfo(&contents[i]))
+	}
+	return buf
+}
+
+// Walk 는 prefix 아래 모든 객체를 페이지 버퍼 하나로 순회한다.
+// fn 이 오류를 반환하면 중단하고 그 오류를 반환한다.
+func (s *Storage) Walk(bucket, prefix string, fn func(ObjectInfo) error) error {
+	var (
+		buf   = make([]ObjectInfo, 0, 1000)
+		token []string
+		next  string
+		err   error
+	)
+
+	for {
+		buf, next, err = s.ListInto(buf, bucket, prefix, 1000, token...)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range buf {
+			if err = fn(obj); err != nil {
+				return err
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+		token = []string{next}
+	}
+}
+
+// ListOptions 는 ListObjects 설정
+type ListOptions struct {
+	Limit      int    // 최대 1000, 0 이면 1000
+	Token      string // 이전 결과의 nextToken
+	StartAfter string // 이 key 다음부터 (첫 페이지에만 적용)
+	Delimiter  string // "/" 이면 하위 디렉터리의 객체는 제외 (ListDirs 참고)
+}
+
+// ListObjects 는 List 와 같지만 key 마다 크기, 수정 시각, ETag 를 함께 반환한다.
+// 동기화/정리 작업에서 key 마다 HeadObject 를 보내지 않아도 된다.
+func (s *Storage) ListObjects(bucket, prefix string, opts ListOptions) ([]ObjectInfo, string, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = 1000
+	}
+
+	output, err := s.listObjects(bucket, prefix, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	list := make([]ObjectInfo, len(output.Contents))
+	for i := range output.Contents {
+		list[i] = objectInfo(&output.Contents[i])
+	}
+
+	return list, aws.ToString(output.NextContinuationToken), nil
+}
+
+// ListDirs 는 prefix 바로 아래의 하위 디렉터리("/" 로 끝나는 prefix) 목록을 반환한다.
+// 폴더 형태로 탐색할 때 prefix 를 "photos/" → "photos/2024/" 순으로 내려가며 호출한다.
+// 모든 페이지를 조회하며, 같은 단계의 객체는 ListObjects 에 Delimiter "/" 를 지정해 조회한다.
+func (s *Storage) ListDirs(bucket, prefix string) ([]string, error) {
+	if err := s.config.Policy.Allow(OpList, prefix); err != nil {
+		return nil, err
+	}
+
+	var (
+		dirs  []string
+		token *string
+	)
+	for {
+		output, err := s.client.ListObjectsV2(s.requestContext(), &s3.ListObjectsV2Input{
+			Bucket:            aws.String(bucket),
+			Prefix:            aws.String(prefix),
+			Delimiter:         aws.String("/"),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return dirs, err
+		}
+
+		for _, p := range output.CommonPrefixes {
+			dirs = append(dirs, aws.ToString(p.Prefix))
+		}
+
+		if output.NextContinuationToken == nil {
+			return dirs, nil
+		}
+		token = output.NextContinuationToken
+	}
+}
+
+func (s *Storage) listObjects(bucket, prefix string, opts ListOptions) (*s3.ListObjectsV2Output, error) {
+	if err := s.config.Policy.Allow(OpList, prefix); err != nil {
+		return nil, err
+	}
+
+	// up to 1,000 keys
+	if opts.Limit > 1000 {
+		opts.Limit = 1000
+	}
+
+	options := s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(int32(opts.Limit)),
+	}
+
+	// ContinuationToken
+	// A token to specify where to start paginating. This is the NextContinuationToken from a previously truncated response.
+	if opts.Token != "" {
+		options.ContinuationToken = aws.String(opts.Token)
+	}
+	if opts.StartAfter != "" {
+		options.StartAfter = aws.String(opts.StartAfter)
+	}
+	if opts.Delimiter != "" {
+		options.Delimiter = aws.String(opts.Delimiter)
+	}
+
+	output, err := s.client.ListObjectsV2(s.requestContext(), &options)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Contents = slices.DeleteFunc(output.Contents, func(obj types.Object) bool {
+		return internalKey(aws.ToString(obj.Key))
+	})
+	return output, nil
+}
+
+// internalKey 는 이 패키지가 관리용으로 만드는 객체인지 확인한다. 목록 결과에서 제외된다.
+// UploadDelta 블록 인덱스(<key>.blocks)도 포함되므로 ".blocks" 로 끝나는 일반 객체는 목록에 나오지 않는다.
+func internalKey(key string) bool {
+	return path.Base(key) == dirStatsName || strings.HasPrefix(key, deleteManifestPrefix) || strings.HasPrefix(key, canaryPrefix) ||
+		strings.HasSuffix(key, blockIndexSuffix)
+}
+
+// listOptions List / ListInto 인자를 ListOptions 로 변환
+func listOptions(length int, token []string) ListOptions {
+	opts := ListOptions{Limit: length}
+	if len(token) > 0 {
+		opts.Token = token[0]
+	}
+	return opts
+}
+
+func objectInfo(obj *types.Object) ObjectInfo {
+	return ObjectInfo{
+		Key:          aws.ToString(obj.Key),
+		Size:         aws.ToInt64(obj.Size),
+		ETag:         strings.Trim(aws.ToString(obj.ETag), `"`),
+		LastModified: aws.ToTime(obj.LastModified),
+		StorageClass: string(obj.StorageClass),
+	}
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestListInto(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for i := range 5 {
+		fake.put("bucket", fmt.Sprintf("a/%d", i), []byte("abc"))
+	}
+	fake.put("bucket", "b/x", []byte("x"))
+
+	buf := make([]ObjectInfo, 0, 10)
+	buf = append(buf, ObjectInfo{Key: "stale"})
+
+	page, next, err := store.ListInto(buf, "bucket", "a/", 3)
+	if err != nil || next == "" || len(page) != 3 || page[0].Key != "a/0" || page[0].Size != 3 || page[0].ETag != md5Hex([]byte("abc")) {
+		t.Fatalf("page %+v, next %q, %v", page, next, err)
+	}
+	if &page[0] != &buf[:1][0] {
+		t.Error("cap 이 충분한데 새 배열을 할당함")
+	}
+
+	page, next, err = store.ListInto(page, "bucket", "a/", 3, next)
+	if err != nil || next != "" || len(page) != 2 || page[0].Key != "a/3" {
+		t.Fatalf("second page %+v, next %q, %v", page, next, err)
+	}
+}
+
+func TestAppendObjectInfosAllocs(t *testing.T) {
+	contents := make([]types.Object, 100)
+	for i := range contents {
+		contents[i] = types.Object{Key: aws.String(fmt.Sprintf("k%d", i)), Size: aws.Int64(1), ETag: aws.String(`"etag"`)}
+	}
+	buf := make([]ObjectInfo, 0, len(contents))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = appendObjectInfos(buf[:0], contents)
+	})
+	if allocs != 0 || len(buf) != len(contents) {
+		t.Errorf("allocs %v, len %d", allocs, len(buf))
+	}
+}
+
+func TestWalk(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	var want []string
+	for i := range 2500 {
+		key := fmt.Sprintf("p/%04d", i)
+		fake.put("bucket", key, []byte("x"))
+		want = append(want, key)
+	}
+	fake.put("bucket", "q/other", []byte("x"))
+
+	var lists atomic.Int64
+	fake.fail = func(r *http.Request) int {
+		if r.URL.Query().Get("list-type") == "2" {
+			lists.Add(1)
+		}
+		return 0
+	}
+
+	var walked []string
+	err := store.Walk("bucket", "p/", func(obj ObjectInfo) error {
+		walked = append(walked, obj.Key)
+		return nil
+	})
+	if err != nil || !slices.Equal(walked, want) || lists.Load() != 3 {
+		t.Fatalf("walked %d, lists %d, %v", len(walked), lists.Load(), err)
+	}
+
+	// fn 의 오류에서 멈춘다
+	errStop := errors.New("stop")
+	var count int
+	err = store.Walk("bucket", "p/", func(obj ObjectInfo) error {
+		if count++; count == 10 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || count != 10 {
+		t.Errorf("count %d, %v", count, err)
+	}
+
+	// 목록 조회 실패
+	fake.fail = func(*http.Request) int { return http.StatusForbidden }
+	if err = store.Walk("bucket", "p/", func(ObjectInfo) error { return nil }); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("list error %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pro200/go-utils"
+)
+
+const (
+	manifestName  = "manifest.json"
+	signatureName = "manifest.sig"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid manifest signature")
+	ErrManifestMismatch = errors.New("artifact does not match manifest")
+)
+
+// Manifest 는 prefix 아래 배포 아티팩트 목록
+type Manifest struct {
+	Created   time.Time           `json:"created"`
+	Artifacts map[string]Artifact `json:"artifacts"` // key: prefix 기준 상대 경로
+}
+
+type Artifact struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// UploadManifest 는 files(상대 경로 → 로컬 파일)를 prefix 아래 업로드하고
+// ed25519 로 서명한 manifest.json / manifest.sig 를 함께 저장한다.
+func (s *Storage) UploadManifest(bucket, prefix string, files map[string]string, privateKey ed25519.PrivateKey) (*Manifest, error) {
+	manifest := &Manifest{
+		Created:   time.Now().UTC(),
+		Artifacts: make(map[string]Artifact, len(files)),
+	}
+
+	for name, path := range files {
+		artifact, err := s.uploadHashed(bucket, prefix+name, path)
+		if err != nil {
+			return nil, fmt.Errorf("upload %s: %w", name, err)
+		}
+		manifest.Artifacts[name] = artifact
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.putBytes(bucket, prefix+manifestName, data, "application/json"); err != nil {
+		return nil, err
+	}
+
+	signature := ed25519.Sign(privateKey, data)
+	if err = s.putBytes(bucket, prefix+signatureName, signature, "application/octet-stream"); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// VerifyManifest 는 manifest 서명을 확인한 뒤 모든 아티팩트의 크기와 SHA-256 을 비교한다.
+func (s *Storage) VerifyManifest(bucket, prefix string, publicKey ed25519.PublicKey) (*Manifest, error) {
+	data, err := s.getBytes(bucket, prefix+manifestName)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.getBytes(bucket, prefix+signatureName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ed25519.Verify(publicKey, data, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var manifest Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for name, expected := range manifest.Artifacts {
+		actual, err := s.hashObject(bucket, prefix+name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		if actual != expected {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrManifestMismatch, name))
+		}
+	}
+
+	return &manifest, errors.Join(errs...)
+}
+
+func (s *Storage) hashObject(bucket, key string) (Artifact, error) {
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer output.Body.Close()
+
+	return hashReader(output.Body)
+}
+
+// uploadHashed 는 파일을 한 번만 읽으며 업로드와 SHA-256 계산을 같이 한다.
+// 그 사이 파일이 바뀌어도 manifest 에는 실제로 올라간 내용의 해시가 기록된다.
+func (s *Storage) uploadHashed(bucket, key, path string) (Artifact, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(file, hash)}
+
+	if stat.Size() == 0 {
+		// UploadReader 는 빈 스트림을 거부함
+		err = s.Upload(bucket, key, path)
+	} else {
+		err = s.UploadReader(bucket, key, counter, WithContentType(utils.ContentType(path)))
+	}
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	return Artifact{Size: counter.n, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func hashReader(r io.Reader) (Artifact, error) {
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	return Artifact{Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.tar.gz": filepath.Join(dir, "app.tar.gz"),
+		"empty.txt":  filepath.Join(dir, "empty.txt"),
+	}
+	os.WriteFile(files["app.tar.gz"], []byte("release"), 0o644)
+	os.WriteFile(files["empty.txt"], nil, 0o644)
+
+	manifest, err := store.UploadManifest("bucket", "releases/v1/", files, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("release"))
+	if artifact := manifest.Artifacts["app.tar.gz"]; artifact.Size != 7 || artifact.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("artifact %+v", artifact)
+	}
+	if string(fake.get("bucket", "releases/v1/app.tar.gz")) != "release" || fake.get("bucket", "releases/v1/"+signatureName) == nil {
+		t.Fatal("업로드되지 않음")
+	}
+
+	verified, err := store.VerifyManifest("bucket", "releases/v1/", publicKey)
+	if err != nil || len(verified.Artifacts) != 2 {
+		t.Fatalf("verify %+v, %v", verified, err)
+	}
+
+	// 다른 키
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if _, err := store.VerifyManifest("bucket", "releases/v1/", otherKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("other key %v", err)
+	}
+
+	// 아티팩트 변조
+	fake.put("bucket", "releases/v1/app.tar.gz", []byte("evil!!!"))
+	if _, err := store.VerifyManifest("bucket", "releases/v1/", publicKey); !errors.Is(err, ErrManifestMismatch) {
+		t.Errorf("tampered artifact %v", err)
+	}
+
+	// manifest 변조
+	data := fake.get("bucket", "releases/v1/"+manifestName)
+	fake.put("bucket", "releases/v1/"+manifestName, append(data, ' '))
+	if _, err := store.VerifyManifest("bucket", "releases/v1/", publicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered manifest %v", err)
+	}
+
+	// 아티팩트 삭제
+	fake.put("bucket", "releases/v1/"+manifestName, data)
+	store.Delete("bucket", "releases/v1/empty.txt")
+	if _, err := store.VerifyManifest("bucket", "releases/v1/", publicKey); !isNotFound(err) {
+		t.Errorf("missing artifact %v", err)
+	}
+}
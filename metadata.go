@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// objectHeaders 는 서버 측 복사로 바꿀 수 있는 객체 헤더와 메타데이터
+type objectHeaders struct {
+	Metadata           map[string]string
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	ContentLanguage    string
+	ACL                string // canned ACL, 예: public-read. HEAD 로 알 수 없으므로 지정할 때만 적용
+
+	grants aclGrants // ACL 을 지정하지 않았을 때 유지할 기존 grant
+}
+
+// aclGrants 는 x-amz-grant-* 헤더 값
+type aclGrants struct {
+	Read, ReadACP, WriteACP, FullControl string
+}
+
+// replaceMetadata 는 객체를 자기 자신에게 복사하면서 헤더를 교체한다.
+// 기존 헤더를 그대로 두고 mutate 가 바꾼 값만 적용되며,
+// 그 사이 객체가 바뀌면(ETag 불일치) 덮어쓰지 않고 실패한다.
+// mutate 후 바뀐 것이 없으면 복사하지 않고 errSkipped 를 반환한다.
+// 복사는 ACL 을 기본값(private)으로 되돌리므로, ACL 을 지정하지 않으면 기존 grant 를 읽어 다시 지정한다.
+// 5GB 를 넘는 객체는 UploadPartCopy 로 나누어 복사한다.
+func (s *Storage) replaceMetadata(bucket, key string, mutate func(*objectHeaders)) error {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return err
+	}
+
+	head, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := &objectHeaders{
+		Metadata:           maps.Clone(head.Metadata),
+		ContentType:        aws.ToString(head.ContentType),
+		CacheControl:       aws.ToString(head.CacheControl),
+		ContentDisposition: aws.ToString(head.ContentDisposition),
+		ContentEncoding:    aws.ToString(head.ContentEncoding),
+		ContentLanguage:    aws.ToString(head.ContentLanguage),
+	}
+	if headers.Metadata == nil {
+		headers.Metadata = map[string]string{}
+	}
+	original := *headers
+	original.Metadata = maps.Clone(headers.Metadata)
+
+	mutate(headers)
+	if headers.equal(&original) {
+		return errSkipped
+	}
+
+	if headers.ACL == "" {
+		if headers.grants, err = s.objectGrants(bucket, key); err != nil {
+			return fmt.Errorf("read acl of %s: %w", key, err)
+		}
+	}
+
+	if aws.ToInt64(head.ContentLength) > maxCopySize {
+		return s.copyMultipart(bucket, key, bucket, key, head, headers, head.StorageClass)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		CopySource:         aws.String(copySource(bucket, key)),
+		CopySourceIfMatch:  head.ETag,
+		MetadataDirective:  types.MetadataDirectiveReplace,
+		Metadata:           headers.Metadata,
+		ContentType:        optionalString(headers.ContentType),
+		CacheControl:       optionalString(headers.CacheControl),
+		ContentDisposition: optionalString(headers.ContentDisposition),
+		ContentEncoding:    optionalString(headers.ContentEncoding),
+		ContentLanguage:    optionalString(headers.ContentLanguage),
+		StorageClass:       head.StorageClass, // 지정하지 않으면 기본 class 로 바뀜
+	}
+	if headers.ACL != "" {
+		input.ACL = types.ObjectCannedACL(headers.ACL)
+	}
+	input.GrantRead = optionalString(headers.grants.Read)
+	input.GrantReadACP = optionalString(headers.grants.ReadACP)
+	input.GrantWriteACP = optionalString(headers.grants.WriteACP)
+	input.GrantFullControl = optionalString(headers.grants.FullControl)
+
+	_, err = s.client.CopyObject(s.requestContext(), input)
+	return err
+}
+
+// objectGrants 는 객체 ACL 을 x-amz-grant-* 헤더 값으로 바꾼다.
+// 소유자 FULL_CONTROL 뿐인 기본 ACL 이나 객체 ACL 이 없는 provider(NotImplemented)는 빈 값으로,
+// 복사할 때 grant 를 지정하지 않는다 (ACL 을 끈 버킷은 grant 헤더를 거부함).
+func (s *Storage) objectGrants(bucket, key string) (aclGrants, error) {
+	output, err := s.client.GetObjectAcl(s.requestContext(), &s3.GetObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if isNotImplemented(err) {
+		return aclGrants{}, nil
+	}
+	if err != nil {
+		return aclGrants{}, err
+	}
+
+	var owner string
+	if output.Owner != nil {
+		owner = aws.ToString(output.Owner.ID)
+	}
+
+	var (
+		lists   = map[types.Permission][]string{}
+		private = true
+	)
+	for _, grant := range output.Grants {
+		if grant.Grantee == nil {
+			continue
+		}
+		grantee := granteeHeader(grant.Grantee)
+		if grantee == "" {
+			continue
+		}
+		if aws.ToString(grant.Grantee.ID) != owner || grant.Permission != types.PermissionFullControl {
+			private = false
+		}
+		lists[grant.Permission] = append(lists[grant.Permission], grantee)
+	}
+	if private {
+		return aclGrants{}, nil
+	}
+
+	return aclGrants{
+		Read:        strings.Join(lists[types.PermissionRead], ", "),
+		ReadACP:     strings.Join(lists[types.PermissionReadAcp], ", "),
+		WriteACP:    strings.Join(lists[types.PermissionWriteAcp], ", "),
+		FullControl: strings.Join(lists[types.PermissionFullControl], ", "),
+	}, nil
+}
+
+// granteeHeader 는 grant 헤더 형식의 grantee, 예: uri="http://acs.amazonaws.com/groups/global/AllUsers"
+func granteeHeader(grantee *types.Grantee) string {
+	switch {
+	case aws.ToString(grantee.URI) != "":
+		return fmt.Sprintf("uri=%q", aws.ToString(grantee.URI))
+	case aws.ToString(grantee.ID) != "":
+		return fmt.Sprintf("id=%q", aws.ToString(grantee.ID))
+	case aws.ToString(grantee.EmailAddress) != "":
+		return fmt.Sprintf("emailAddress=%q", aws.ToString(grantee.EmailAddress))
+	}
+	return ""
+}
+
+// isNotImplemented provider 가 지원하지 않는 API
+func isNotImplemented(err error) bool {
+	if errorCode(err) == "NotImplemented" {
+		return true
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotImplemented
+}
+
+func (h *objectHeaders) equal(other *objectHeaders) bool {
+	return h.ContentType == other.ContentType &&
+		h.CacheControl == other.CacheControl &&
+		h.ContentDisposition == other.ContentDisposition &&
+		h.ContentEncoding == other.ContentEncoding &&
+		h.ContentLanguage == other.ContentLanguage &&
+		h.ACL == other.ACL &&
+		maps.Equal(h.Metadata, other.Metadata)
+}
+
+// objectMetadata 는 Options.Metadata 에 created-by, expires-at 을 더한 업로드 메타데이터
+func (s *Storage) objectMetadata(opt *Options) map[string]string {
+	lifecycle := s.lifecycleMetadata(opt)
+	if len(opt.Metadata) == 0 {
+		return lifecycle
+	}
+
+	metadata := make(map[string]string, len(opt.Metadata)+len(lifecycle))
+	for name, value := range opt.Metadata {
+		metadata[strings.ToLower(name)] = value
+	}
+	maps.Copy(metadata, lifecycle)
+	return metadata
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
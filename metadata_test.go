@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestObjectHeadersEqual(t *testing.T) {
+	a := &objectHeaders{CacheControl: "max-age=60", Metadata: map[string]string{"owner": "42"}}
+	b := &objectHeaders{CacheControl: "max-age=60", Metadata: map[string]string{"owner": "42"}}
+	if !a.equal(b) {
+		t.Error("같은 헤더")
+	}
+
+	b.ACL = "public-read"
+	if a.equal(b) {
+		t.Error("ACL 이 다름")
+	}
+
+	b.ACL = ""
+	b.Metadata["owner"] = "43"
+	if a.equal(b) {
+		t.Error("메타데이터가 다름")
+	}
+}
+
+func TestUpdateMetadataBulk(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for _, key := range []string{"site/a.html", "site/b.html", "site/done.html", "other/c.html"} {
+		if err := store.putBytes("bucket", key, []byte(key), "text/html"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.objects["bucket/site/a.html"].acl = "public-read"
+	fake.objects["bucket/site/done.html"].headers.Set("Cache-Control", "max-age=60")
+	fake.objects["bucket/site/b.html"].metadata["old"] = "1"
+
+	var calls atomic.Int64
+	progress, err := store.UpdateMetadataBulk("bucket", "site/", func(obj ObjectInfo) map[string]string {
+		return map[string]string{"cache-control": "max-age=60", "old": ""}
+	}, BulkOptions{Concurrency: 2, Progress: func(BulkProgress) { calls.Add(1) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != (BulkProgress{Scanned: 3, Updated: 2}) || calls.Load() != 3 {
+		t.Fatalf("progress %+v, calls %d", progress, calls.Load())
+	}
+
+	for _, key := range []string{"site/a.html", "site/b.html"} {
+		object := fake.objects["bucket/"+key]
+		if object.headers.Get("Cache-Control") != "max-age=60" || object.headers.Get("Content-Type") != "text/html" {
+			t.Errorf("%s: headers %v", key, object.headers)
+		}
+		if _, ok := object.metadata["old"]; ok {
+			t.Errorf("%s: old 메타데이터가 남음", key)
+		}
+	}
+	if fake.objects["bucket/other/c.html"].headers.Get("Cache-Control") != "" {
+		t.Error("prefix 밖의 객체가 바뀜")
+	}
+
+	// 복사가 ACL 을 private 로 되돌리지 않아야 한다
+	if acl := fake.objects["bucket/site/a.html"].acl; acl != "public-read" {
+		t.Errorf("a.html acl = %s", acl)
+	}
+	if acl := fake.objects["bucket/site/b.html"].acl; acl != "private" {
+		t.Errorf("b.html acl = %s", acl)
+	}
+}
+
+func TestUpdateMetadataBulkErrors(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for _, key := range []string{"a", "b"} {
+		if err := store.putBytes("bucket", key, []byte(key), "text/plain"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.fail = func(r *http.Request) int {
+		if r.Method == http.MethodPut && r.URL.Path == "/bucket/b" {
+			return http.StatusInternalServerError
+		}
+		return 0
+	}
+
+	var failed []string
+	progress, err := store.ApplyHeadersRecursive("bucket", "", map[string]string{"acl": "public-read"}, BulkOptions{
+		OnError: func(key string, err error) { failed = append(failed, key) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != (BulkProgress{Scanned: 2, Updated: 1, Failed: 1}) || len(failed) != 1 || failed[0] != "b" {
+		t.Fatalf("progress %+v, failed %v", progress, failed)
+	}
+	if fake.objects["bucket/a"].acl != "public-read" || fake.objects["bucket/b"].acl != "private" {
+		t.Error("명시한 ACL 이 적용되지 않음")
+	}
+}
+
+func TestReplaceMetadataWithoutObjectACL(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	if err := store.putBytes("bucket", "a", []byte("a"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	// R2 처럼 객체 ACL 을 지원하지 않는 provider
+	fake.fail = func(r *http.Request) int {
+		if r.URL.Query().Has("acl") {
+			return http.StatusNotImplemented
+		}
+		return 0
+	}
+	if err := store.SetNote("bucket", "a", "checked"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 그 밖의 ACL 조회 실패는 복사하지 않고 오류
+	fake.fail = func(r *http.Request) int {
+		if r.URL.Query().Has("acl") {
+			return http.StatusForbidden
+		}
+		return 0
+	}
+	if err := store.SetNote("bucket", "a", "again"); err == nil {
+		t.Fatal("ACL 을 읽지 못했는데 복사함")
+	}
+	if note, _ := store.GetNote("bucket", "a"); note != "checked" {
+		t.Errorf("note = %q", note)
+	}
+}
+
+func TestCopyMultipartKeepsGrants(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	data := []byte("large object")
+	if err := store.putBytes("bucket", "big", data, "video/mp4"); err != nil {
+		t.Fatal(err)
+	}
+	fake.objects["bucket/big"].acl = "public-read"
+
+	grants, err := store.objectGrants("bucket", "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grants.Read != `uri="`+allUsersURI+`"` || grants.FullControl != `id="owner"` {
+		t.Fatalf("grants %+v", grants)
+	}
+
+	head, err := store.client.HeadObject(store.requestContext(), &s3.HeadObjectInput{Bucket: aws.String("bucket"), Key: aws.String("big")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := &objectHeaders{ContentType: "video/mp4", CacheControl: "max-age=60", Metadata: map[string]string{}, grants: grants}
+	if err := store.copyMultipart("bucket", "big", "bucket", "big", head, headers, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	object := fake.objects["bucket/big"]
+	if string(object.data) != string(data) || object.acl != "public-read" || object.headers.Get("Cache-Control") != "max-age=60" {
+		t.Errorf("data %q, acl %s, headers %v", object.data, object.acl, object.headers)
+	}
+
+	// 소유자 권한뿐이면 grant 헤더를 보내지 않는다
+	object.acl = "private"
+	if grants, err := store.objectGrants("bucket", "big"); err != nil || grants != (aclGrants{}) {
+		t.Errorf("private grants %+v, %v", grants, err)
+	}
+}
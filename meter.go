@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	awsMiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyHttp "github.com/aws/smithy-go/transport/http"
+)
+
+// 과금 기준 작업 분류 (R2 / S3 의 Class A, Class B). 목록에 없는 작업은 Class A.
+var freeOperations = map[string]bool{
+	"DeleteObject":         true,
+	"DeleteObjects":        true,
+	"AbortMultipartUpload": true,
+}
+
+var classBOperations = map[string]bool{
+	"GetObject":  true,
+	"HeadObject": true,
+	"HeadBucket": true,
+}
+
+// Usage 는 Storage 인스턴스가 생성된 이후 누적된 API 사용량
+type Usage struct {
+	Operations map[string]int64 // API 작업 이름 → 요청 수 (재시도 포함)
+	ClassA     int64
+	ClassB     int64
+	BytesIn    int64 // 업로드
+	BytesOut   int64 // 다운로드 (egress)
+	Retries    int64 // 재시도 요청 수 (Operations 에 포함)
+	Errors     int64 // 재시도 후에도 실패한 호출 수
+}
+
+// String 은 주기적인 로그용 요약
+func (u Usage) String() string {
+	var requests int64
+	for _, n := range u.Operations {
+		requests += n
+	}
+	return fmt.Sprintf("requests=%d (A=%d B=%d) retries=%d errors=%d in=%dB out=%dB",
+		requests, u.ClassA, u.ClassB, u.Retries, u.Errors, u.BytesIn, u.BytesOut)
+}
+
+// PriceTable 단위: ClassA/ClassB 는 백만 건당, Egress 는 GB 당 (USD)
+type PriceTable struct {
+	ClassA float64
+	ClassB float64
+	Egress float64
+}
+
+var (
+	R2Prices = PriceTable{ClassA: 4.50, ClassB: 0.36, Egress: 0}
+	S3Prices = PriceTable{ClassA: 5.00, ClassB: 0.40, Egress: 0.09}
+	B2Prices = PriceTable{ClassA: 0, ClassB: 0.40, Egress: 0.01}
+)
+
+func (s *Storage) Usage() Usage {
+	return s.meter.usage()
+}
+
+// EstimateCost 는 누적 사용량에 prices 를 적용한 예상 API/전송 비용 (저장 용량 비용 제외)
+func (s *Storage) EstimateCost(prices PriceTable) float64 {
+	usage := s.meter.usage()
+
+	return float64(usage.ClassA)/1e6*prices.ClassA +
+		float64(usage.ClassB)/1e6*prices.ClassB +
+		float64(usage.BytesOut)/(1<<30)*prices.Egress
+}
+
+type meter struct {
+	mu    sync.Mutex
+	stats Usage
+}
+
+func newMeter() *meter {
+	return &meter{stats: Usage{Operations: map[string]int64{}}}
+}
+
+func (m *meter) usage() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage := m.stats
+	usage.Operations = maps.Clone(m.stats.Operations)
+	return usage
+}
+
+func (m *meter) record(operation string, bytesIn, bytesOut int64, retry bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.Operations[operation]++
+	if retry {
+		m.stats.Retries++
+	}
+	m.stats.BytesIn += bytesIn
+	m.stats.BytesOut += bytesOut
+
+	switch {
+	case freeOperations[operation]:
+	case classBOperations[operation]:
+		m.stats.ClassB++
+	default:
+		m.stats.ClassA++
+	}
+}
+
+func (m *meter) recordError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.Errors++
+}
+
+// addMiddleware 는 모든 S3 요청(재시도 포함)을 transport 직전에서, 최종 실패는 호출 단위로 집계한다.
+func (m *meter) addMiddleware(stack *middleware.Stack) error {
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("StorageMeterError", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		if err != nil {
+			m.recordError()
+		}
+		return out, metadata, err
+	}), middleware.After)
+	if err != nil {
+		return err
+	}
+
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("StorageMeter", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (middleware.DeserializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleDeserialize(ctx, in)
+
+		var (
+			bytesIn, bytesOut int64
+			retry             bool
+		)
+		if req, ok := in.Request.(*smithyHttp.Request); ok {
+			bytesIn = max(req.ContentLength, 0)
+			retry = isRetryAttempt(req.Header.Get("amz-sdk-request"))
+		}
+
+		operation := awsMiddleware.GetOperationName(ctx)
+		if resp, ok := out.RawResponse.(*smithyHttp.Response); ok && operation == "GetObject" && resp.ContentLength > 0 {
+			bytesOut = resp.ContentLength
+		}
+
+		m.record(operation, bytesIn, bytesOut, retry)
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// isRetryAttempt 는 SDK 가 붙이는 "attempt=2; max=3" 형식 헤더에서 두 번째 이후 시도인지 판단한다.
+func isRetryAttempt(header string) bool {
+	for field := range strings.SplitSeq(header, ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(field), "attempt="); ok {
+			n, err := strconv.Atoi(value)
+			return err == nil && n > 1
+		}
+	}
+	return false
+}
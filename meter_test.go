@@ -0,0 +1,32 @@
+package storage
+
+import "testing"
+
+func TestMeter(t *testing.T) {
+	m := newMeter()
+	m.record("PutObject", 10, 0, false)
+	m.record("PutObject", 10, 0, true)
+	m.record("GetObject", 0, 5, false)
+	m.recordError()
+
+	usage := m.usage()
+	if usage.ClassA != 2 || usage.ClassB != 1 || usage.Retries != 1 || usage.Errors != 1 || usage.BytesIn != 20 || usage.BytesOut != 5 {
+		t.Errorf("%+v", usage)
+	}
+	if got := usage.String(); got != "requests=3 (A=2 B=1) retries=1 errors=1 in=20B out=5B" {
+		t.Error(got)
+	}
+}
+
+func TestIsRetryAttempt(t *testing.T) {
+	for header, want := range map[string]bool{
+		"attempt=1; max=3": false,
+		"attempt=2; max=3": true,
+		"max=3; attempt=3": true,
+		"":                 false,
+	} {
+		if got := isRetryAttempt(header); got != want {
+			t.Errorf("%q: %v", header, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// canary 객체 기본 위치
+const canaryPrefix = ".canary/"
+
+// Monitor 는 작은 canary 객체를 주기적으로 PUT / GET / DELETE 하여 스토리지 상태를 확인한다.
+// 사용자가 겪기 전에 provider 장애나 지연을 감지하는 용도.
+type Monitor struct {
+	Interval time.Duration // default: 1m
+	Key      string        // canary 객체 key, default: ".canary/<random>"
+	OnResult func(CanaryResult)
+
+	storage *Storage
+	bucket  string
+
+	mu    sync.Mutex
+	last  CanaryResult
+	stats MonitorStats
+}
+
+// CanaryResult 는 한 번의 확인 결과, 실패하면 Op 에 실패한 단계가 기록된다.
+type CanaryResult struct {
+	Time   time.Time
+	Put    time.Duration
+	Get    time.Duration
+	Delete time.Duration
+	Op     Operation // 실패한 단계, 성공이면 ""
+	Err    error
+}
+
+func (r CanaryResult) OK() bool {
+	return r.Err == nil
+}
+
+type MonitorStats struct {
+	Runs                int64
+	Failures            int64
+	ConsecutiveFailures int64
+}
+
+func (s *Storage) Monitor(bucket string) *Monitor {
+	random := make([]byte, 6)
+	rand.Read(random)
+
+	return &Monitor{
+		Interval: time.Minute,
+		Key:      canaryPrefix + hex.EncodeToString(random),
+		storage:  s,
+		bucket:   bucket,
+	}
+}
+
+// Run 은 ctx 가 끝날 때까지 Interval 마다 Check 를 실행한다. Interval 이 0 이하이면 1m.
+func (m *Monitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check 는 canary 확인을 한 번 실행하고 결과를 기록한 뒤 OnResult 로 전달한다.
+func (m *Monitor) Check() CanaryResult {
+	result := m.canary()
+
+	m.mu.Lock()
+	m.last = result
+	m.stats.Runs++
+	if result.OK() {
+		m.stats.ConsecutiveFailures = 0
+	} else {
+		m.stats.Failures++
+		m.stats.ConsecutiveFailures++
+	}
+	m.mu.Unlock()
+
+	if m.OnResult != nil {
+		m.OnResult(result)
+	}
+	return result
+}
+
+// Last 마지막 확인 결과
+func (m *Monitor) Last() CanaryResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+func (m *Monitor) Stats() MonitorStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+func (m *Monitor) canary() CanaryResult {
+	result := CanaryResult{Time: time.Now()}
+	payload := []byte(result.Time.UTC().Format(time.RFC3339Nano))
+
+	start := time.Now()
+	if err := m.storage.putBytes(m.bucket, m.Key, payload, "text/plain"); err != nil {
+		result.Op, result.Err = OpPut, err
+		return result
+	}
+	result.Put = time.Since(start)
+
+	start = time.Now()
+	data, err := m.storage.getBytes(m.bucket, m.Key)
+	result.Get = time.Since(start)
+	if err == nil && !bytes.Equal(data, payload) {
+		err = fmt.Errorf("canary content mismatch: %q", data)
+	}
+	if err != nil {
+		result.Op, result.Err = OpGet, err
+		return result
+	}
+
+	start = time.Now()
+	if err = m.storage.Delete(m.bucket, m.Key); err != nil {
+		result.Op, result.Err = OpDelete, err
+		return result
+	}
+	result.Delete = time.Since(start)
+
+	return result
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	m := store.Monitor("bucket")
+
+	if result := m.Check(); !result.OK() || result.Op != "" {
+		t.Fatalf("%+v", result)
+	}
+	if keys := fake.keys("bucket"); len(keys) != 0 {
+		t.Fatalf("canary left behind: %v", keys)
+	}
+
+	// PUT 실패
+	fake.fail = func(r *http.Request) int {
+		if r.Method == http.MethodPut {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+	m.Check()
+	if result := m.Check(); result.OK() || result.Op != OpPut {
+		t.Fatalf("%+v", result)
+	}
+	if stats := m.Stats(); stats.Runs != 3 || stats.Failures != 2 || stats.ConsecutiveFailures != 2 {
+		t.Fatalf("%+v", stats)
+	}
+
+	// 성공하면 연속 실패 수 초기화
+	fake.fail = nil
+	m.Check()
+	if stats := m.Stats(); stats.Failures != 2 || stats.ConsecutiveFailures != 0 || !m.Last().OK() {
+		t.Fatalf("%+v", stats)
+	}
+}
+
+func TestMonitorRunDefaultInterval(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := store.Monitor("bucket")
+	m.Interval = 0
+	m.OnResult = func(CanaryResult) { cancel() }
+
+	if err := m.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if m.Stats().Runs != 1 {
+		t.Fatalf("%+v", m.Stats())
+	}
+}
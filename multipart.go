@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// CleanAbandonedMultipartUploads 는 olderThan 보다 오래된 미완료 multipart 업로드를 중단(abort)한다.
+// 완료되지 않은 part 도 저장 용량으로 과금되므로 주기적으로 실행하는 것을 권장.
+// Policy 에서 삭제가 허용되지 않는 key 는 건너뛴다. 반환값은 중단한 업로드 수.
+// Config.Blackouts 시간대에는 멈췄다가 이어서 진행한다.
+func (s *Storage) CleanAbandonedMultipartUploads(bucket string, olderThan time.Duration) (int, error) {
+	var (
+		aborted        int
+		keyMarker      *string
+		uploadIdMarker *string
+		cutoff         = time.Now().Add(-olderThan)
+	)
+
+	for {
+		if _, err := s.WaitBlackout(); err != nil {
+			return aborted, err
+		}
+
+		output, err := s.client.ListMultipartUploads(s.requestContext(), &s3.ListMultipartUploadsInput{
+			Bucket:         aws.String(bucket),
+			KeyMarker:      keyMarker,
+			UploadIdMarker: uploadIdMarker,
+		})
+		if err != nil {
+			return aborted, err
+		}
+
+		for _, upload := range output.Uploads {
+			if upload.Initiated == nil || upload.Initiated.After(cutoff) {
+				continue
+			}
+			if s.config.Policy.Allow(OpDelete, aws.ToString(upload.Key)) != nil {
+				continue
+			}
+
+			_, err = s.client.AbortMultipartUpload(s.requestContext(), &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			// 그 사이 완료/중단된 업로드는 무시
+			if err != nil && errorCode(err) != "NoSuchUpload" {
+				return aborted, err
+			}
+			if err == nil {
+				aborted++
+			}
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return aborted, nil
+		}
+		keyMarker = output.NextKeyMarker
+		uploadIdMarker = output.NextUploadIdMarker
+	}
+}
+
+// MultipartUploadInfo 는 완료되지 않은(진행 중이거나 멈춘) multipart 업로드
+type MultipartUploadInfo struct {
+	Key          string    `json:"key"`
+	UploadID     string    `json:"upload_id"`
+	Initiated    time.Time `json:"initiated"`
+	StorageClass string    `json:"storage_class,omitempty"`
+}
+
+// PartInfo 는 multipart 업로드에 올라간 part
+type PartInfo struct {
+	Number       int32     `json:"number"`
+	ETag         string    `json:"etag"` // 따옴표 제거
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// MultipartUploads 는 prefix 아래 완료되지 않은 multipart 업로드를 key 순으로 반환한다.
+func (s *Storage) MultipartUploads(bucket, prefix string) ([]MultipartUploadInfo, error) {
+	if err := s.config.Policy.Allow(OpList, prefix); err != nil {
+		return nil, err
+	}
+
+	var (
+		uploads        []MultipartUploadInfo
+		keyMarker      *string
+		uploadIdMarker *string
+	)
+	for {
+		output, err := s.client.ListMultipartUploads(s.requestContext(), &s3.ListMultipartUploadsInput{
+			Bucket:         aws.String(bucket),
+			Prefix:         aws.String(prefix),
+			KeyMarker:      keyMarker,
+			UploadIdMarker: uploadIdMarker,
+		})
+		if err != nil {
+			return uploads, err
+		}
+
+		for _, upload := range output.Uploads {
+			uploads = append(uploads, MultipartUploadInfo{
+				Key:          aws.ToString(upload.Key),
+				UploadID:     aws.ToString(upload.UploadId),
+				Initiated:    aws.ToTime(upload.Initiated),
+				StorageClass: string(upload.StorageClass),
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return uploads, nil
+		}
+		keyMarker = output.NextKeyMarker
+		uploadIdMarker = output.NextUploadIdMarker
+	}
+}
+
+// ListParts 는 완료되지 않은 multipart 업로드에 지금까지 올라간 part 를 번호 순으로 반환한다.
+// 완료되었거나 중단된 업로드이면 provider 오류(NoSuchUpload)를 반환한다.
+func (s *Storage) ListParts(bucket, key, uploadID string) ([]PartInfo, error) {
+	if err := s.config.Policy.Allow(OpList, key); err != nil {
+		return nil, err
+	}
+
+	var (
+		parts  []PartInfo
+		marker *string
+	)
+	for {
+		output, err := s.client.ListParts(s.requestContext(), &s3.ListPartsInput{
+			Bucket:           aws.String(bucket),
+			Key:              aws.String(key),
+			UploadId:         aws.String(uploadID),
+			PartNumberMarker: marker,
+		})
+		if err != nil {
+			return parts, err
+		}
+
+		for _, part := range output.Parts {
+			parts = append(parts, PartInfo{
+				Number:       aws.ToInt32(part.PartNumber),
+				ETag:         strings.Trim(aws.ToString(part.ETag), `"`),
+				Size:         aws.ToInt64(part.Size),
+				LastModified: aws.ToTime(part.LastModified),
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			return parts, nil
+		}
+		marker = output.NextPartNumberMarker
+	}
+}
+
+// PartCount 는 multipart 로 올라간 객체의 part 수. ETag 가 "<md5>-<part 수>" 형식이 아니면 0.
+// 복사(CopyObject)로 만든 객체는 원본과 다를 수 있다.
+func (o ObjectInfo) PartCount() int {
+	_, count, ok := strings.Cut(o.ETag, "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+// uploaderOptions 는 Config 의 multipart 설정을 적용한다. 0 이면 SDK 기본값 유지. New 에서 한 번 사용한다.
+func (c Config) uploaderOptions(u *manager.Uploader) {
+	if c.PartSize > 0 {
+		u.PartSize = c.PartSize
+	}
+	if c.Concurrency > 0 {
+		u.Concurrency = c.Concurrency
+	}
+	if c.MaxUploadParts > 0 {
+		u.MaxUploadParts = c.MaxUploadParts
+	}
+}
+
+func (c Config) downloaderOptions(d *manager.Downloader) {
+	if c.PartSize > 0 {
+		d.PartSize = c.PartSize
+	}
+	if c.Concurrency > 0 {
+		d.Concurrency = c.Concurrency
+	}
+}
+
+// uploaderOptions 는 호출별 옵션을 덮어 적용한다. SDK 가 호출마다 설정을 복사하므로 공유 uploader 는 바뀌지 않는다.
+func (opt *Options) uploaderOptions(u *manager.Uploader) {
+	if opt.PartSize > 0 {
+		u.PartSize = opt.PartSize
+	}
+	if opt.Concurrency > 0 {
+		u.Concurrency = opt.Concurrency
+	}
+}
+
+func (opt *downloadOptions) downloaderOptions(d *manager.Downloader) {
+	if opt.partSize > 0 {
+		d.PartSize = opt.partSize
+	}
+	if opt.concurrency > 0 {
+		d.Concurrency = opt.concurrency
+	}
+}
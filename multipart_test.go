@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPartCount(t *testing.T) {
+	for etag, want := range map[string]int{
+		"d41d8cd98f00b204e9800998ecf8427e":    0,
+		"d41d8cd98f00b204e9800998ecf8427e-12": 12,
+		"d41d8cd98f00b204e9800998ecf8427e-x":  0,
+		"":                                    0,
+	} {
+		if got := (ObjectInfo{ETag: etag}).PartCount(); got != want {
+			t.Errorf("%q: %d, want %d", etag, got, want)
+		}
+	}
+}
+
+func TestCleanAbandonedMultipartUploads(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Policy: &Policy{Rules: []Rule{
+		{Operations: []Operation{OpDelete}, Prefixes: []string{"tmp/"}},
+		{Operations: []Operation{OpPut, OpList, OpGet, OpInfo}},
+	}}})
+
+	for _, key := range []string{"tmp/old", "tmp/new", "keep/old"} {
+		if _, err := store.CreateMultipart("bucket", key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.CreateMultipart("other", "tmp/old"); err != nil {
+		t.Fatal(err)
+	}
+
+	// tmp/old, keep/old 와 다른 버킷의 업로드는 하루 전에 시작된 것으로
+	old := time.Now().Add(-24 * time.Hour)
+	for _, upload := range fake.uploads {
+		if upload.key != "tmp/new" {
+			upload.initiated = old
+		}
+	}
+
+	aborted, err := store.CleanAbandonedMultipartUploads("bucket", time.Hour)
+	if err != nil || aborted != 1 {
+		t.Fatalf("aborted %d, %v", aborted, err)
+	}
+
+	var remaining []string
+	for _, upload := range fake.uploads {
+		remaining = append(remaining, upload.bucket+"/"+upload.key)
+	}
+	slices.Sort(remaining)
+	// 새 업로드, Policy 로 삭제할 수 없는 key, 다른 버킷은 남는다
+	if !slices.Equal(remaining, []string{"bucket/keep/old", "bucket/tmp/new", "other/tmp/old"}) {
+		t.Errorf("remaining %v", remaining)
+	}
+}
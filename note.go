@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"net/url"
+)
+
+// 메타데이터 값은 HTTP 헤더로 전송되므로 ASCII 만 안전하다. 메모는 URL 인코딩하여 저장.
+const metaNote = "note"
+
+// SetNote 는 객체에 짧은 메모를 남긴다 (예: "2024-06 백업 검증 완료").
+// 메모는 사용자 메타데이터에 저장되며, 빈 문자열이면 삭제한다.
+// 메타데이터는 합쳐서 2KB 까지이므로 긴 내용은 저장할 수 없다.
+func (s *Storage) SetNote(bucket, key, note string) error {
+	err := s.replaceMetadata(bucket, key, func(headers *objectHeaders) {
+		if note == "" {
+			delete(headers.Metadata, metaNote)
+		} else {
+			headers.Metadata[metaNote] = url.QueryEscape(note)
+		}
+	})
+	if err == errSkipped {
+		return nil
+	}
+	return err
+}
+
+// GetNote 메모가 없으면 ""
+func (s *Storage) GetNote(bucket, key string) (string, error) {
+	info, err := s.Info(bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeNote(info.Metadata[metaNote]), nil
+}
+
+func decodeNote(value string) string {
+	note, err := url.QueryUnescape(value)
+	if err != nil {
+		// 다른 도구로 직접 넣은 값
+		return value
+	}
+	return note
+}
@@ -0,0 +1,52 @@
+package storage
+
+import "testing"
+
+func TestNote(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	if err := store.putBytes("bucket", "backup.tar", []byte("data"), "application/x-tar"); err != nil {
+		t.Fatal(err)
+	}
+	fake.objects["bucket/backup.tar"].acl = "public-read"
+	etag := fake.objects["bucket/backup.tar"].etag
+
+	const note = "백업 검증 완료 2024-06 & ok"
+	if err := store.SetNote("bucket", "backup.tar", note); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetNote("bucket", "backup.tar"); err != nil || got != note {
+		t.Fatalf("GetNote %q, %v", got, err)
+	}
+
+	info, err := store.InfoObject("bucket", "backup.tar")
+	if err != nil || info.Note != note || info.ContentType != "application/x-tar" {
+		t.Fatalf("InfoObject %+v, %v", info, err)
+	}
+	object := fake.objects["bucket/backup.tar"]
+	if string(object.data) != "data" || object.etag != etag || object.acl != "public-read" {
+		t.Errorf("object %+v", object)
+	}
+
+	// 같은 메모는 복사하지 않음
+	fake.objects["bucket/backup.tar"].acl = "marker"
+	if err := store.SetNote("bucket", "backup.tar", note); err != nil || fake.objects["bucket/backup.tar"].acl != "marker" {
+		t.Errorf("same note copied: %v", err)
+	}
+
+	// 다른 도구로 넣은 디코딩할 수 없는 값은 그대로
+	fake.objects["bucket/backup.tar"].metadata[metaNote] = "100%"
+	if got, _ := store.GetNote("bucket", "backup.tar"); got != "100%" {
+		t.Errorf("raw note %q", got)
+	}
+
+	if err := store.SetNote("bucket", "backup.tar", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetNote("bucket", "backup.tar"); err != nil || got != "" {
+		t.Errorf("deleted note %q, %v", got, err)
+	}
+
+	if err := store.SetNote("bucket", "missing", note); !isNotFound(err) {
+		t.Errorf("missing object %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBucketNotification(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+
+	if notifications, err := store.GetBucketNotification("bucket"); err != nil || len(notifications) != 0 {
+		t.Fatalf("empty: %+v, %v", notifications, err)
+	}
+
+	want := []Notification{
+		{ID: "images", QueueArn: "arn:aws:sqs:us-east-1:123:images", Events: []string{"s3:ObjectCreated:*"}, Prefix: "images/", Suffix: ".jpg"},
+		{QueueArn: "arn:aws:sqs:us-east-1:123:all", Events: []string{"s3:ObjectCreated:Put", "s3:ObjectRemoved:*"}},
+	}
+	if err := store.PutBucketNotification("bucket", want...); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetBucketNotification("bucket")
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	// 빈 목록이면 모두 삭제
+	if err := store.PutBucketNotification("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.GetBucketNotification("bucket"); err != nil || len(got) != 0 {
+		t.Fatalf("cleared: %+v, %v", got, err)
+	}
+}
+
+func TestBucketNotificationPolicy(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{Policy: &Policy{Rules: []Rule{
+		{Operations: []Operation{OpGet, OpPut, OpList}},
+	}}})
+
+	var requests atomic.Int64
+	fake.fail = func(*http.Request) int {
+		requests.Add(1)
+		return 0
+	}
+
+	if err := store.PutBucketNotification("bucket", Notification{QueueArn: "arn", Events: []string{"s3:ObjectCreated:*"}}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("put: %v", err)
+	}
+	if _, err := store.GetBucketNotification("bucket"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("get: %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("거부된 작업이 요청을 보냄: %d", requests.Load())
+	}
+}
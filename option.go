@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"io"
+	"maps"
+	"sync"
+)
+
+// UploadOption 은 Upload, UploadReader, Copy 동작을 바꾼다.
+// Options 도 UploadOption 이므로 기존처럼 Options{...} 를 넘겨도 된다.
+//
+//	store.Upload(bucket, key, path, storage.WithContentType("image/webp"), storage.WithMetadata(meta))
+type UploadOption interface {
+	applyUpload(*Options)
+}
+
+// Option 은 업로드와 다운로드에 모두 쓸 수 있는 옵션
+type Option interface {
+	UploadOption
+	DownloadOption
+}
+
+type uploadOptionFunc func(*Options)
+
+func (fn uploadOptionFunc) applyUpload(o *Options) {
+	fn(o)
+}
+
+func WithContentType(contentType string) UploadOption {
+	return uploadOptionFunc(func(o *Options) {
+		o.ContentType = contentType
+	})
+}
+
+// WithMetadata 는 사용자 메타데이터를 추가한다. 여러 번 지정하면 합쳐진다.
+func WithMetadata(metadata map[string]string) UploadOption {
+	return uploadOptionFunc(func(o *Options) {
+		if o.Metadata == nil {
+			o.Metadata = map[string]string{}
+		}
+		maps.Copy(o.Metadata, metadata)
+	})
+}
+
+func WithStorageClass(storageClass string) UploadOption {
+	return uploadOptionFunc(func(o *Options) {
+		o.StorageClass = storageClass
+	})
+}
+
+func WithChecksum(algorithm ChecksumAlgorithm) UploadOption {
+	return uploadOptionFunc(func(o *Options) {
+		o.Checksum = algorithm
+	})
+}
+
+// WithPartSize 는 multipart 업로드/병렬 다운로드의 part 크기를 바꾼다. Config.PartSize 보다 우선한다.
+func WithPartSize(size int64) Option {
+	return transferOption{partSize: size}
+}
+
+// WithConcurrency 는 동시에 전송할 part 수를 바꾼다. Config.Concurrency 보다 우선한다.
+func WithConcurrency(n int) Option {
+	return transferOption{concurrency: n}
+}
+
+type transferOption struct {
+	partSize    int64
+	concurrency int
+}
+
+func (t transferOption) applyUpload(o *Options) {
+	if t.partSize > 0 {
+		o.PartSize = t.partSize
+	}
+	if t.concurrency > 0 {
+		o.Concurrency = t.concurrency
+	}
+}
+
+func (t transferOption) applyDownload(o *downloadOptions) {
+	if t.partSize > 0 {
+		o.partSize = t.partSize
+	}
+	if t.concurrency > 0 {
+		o.concurrency = t.concurrency
+	}
+}
+
+// WithProgress 는 전송한 누적 byte 수와 전체 크기를 fn 으로 알린다. 호출은 직렬화된다.
+// 전체 크기를 알 수 없으면(UploadReader, Content-Length 없는 원격 파일) total 은 -1.
+// 업로드 중 재시도가 일어나면 다시 읽은 만큼 더해질 수 있다. Copy 에서는 무시된다.
+func WithProgress(fn func(transferred, total int64)) Option {
+	return progressOption(fn)
+}
+
+type progressOption func(transferred, total int64)
+
+func (fn progressOption) applyUpload(o *Options) {
+	o.progress = fn
+}
+
+func (fn progressOption) applyDownload(o *downloadOptions) {
+	o.progress = fn
+}
+
+// progress 는 여러 goroutine 에서 전송한 byte 를 합산해 fn 을 호출한다.
+type progress struct {
+	mu          sync.Mutex
+	transferred int64
+	total       int64
+	fn          func(transferred, total int64)
+}
+
+func (p *progress) add(n int) {
+	if n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.transferred += int64(n)
+	p.fn(p.transferred, p.total)
+}
+
+type progressReader struct {
+	r io.Reader
+	p *progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.add(n)
+	return n, err
+}
+
+// readerAtSeeker 이면 manager 가 part 를 위치별로 동시에 읽는다.
+type readerAtSeeker interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// progressReaderAt 은 progressReader 와 같지만 ReadAt, Seek 을 유지해 동시 part 읽기를 막지 않는다.
+type progressReaderAt struct {
+	readerAtSeeker
+	p *progress
+}
+
+func (r *progressReaderAt) Read(b []byte) (int, error) {
+	n, err := r.readerAtSeeker.Read(b)
+	r.p.add(n)
+	return n, err
+}
+
+func (r *progressReaderAt) ReadAt(b []byte, offset int64) (int, error) {
+	n, err := r.readerAtSeeker.ReadAt(b, offset)
+	r.p.add(n)
+	return n, err
+}
+
+type progressWriterAt struct {
+	w io.WriterAt
+	p *progress
+}
+
+func (w *progressWriterAt) WriteAt(b []byte, offset int64) (int, error) {
+	n, err := w.w.WriteAt(b, offset)
+	w.p.add(n)
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *progress
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.p.add(n)
+	return n, err
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+)
+
+func TestUploadOptions(t *testing.T) {
+	s := &Storage{profiles: &profiles{}}
+	s.SetProfile("bucket", Options{CacheControl: "no-cache", StorageClass: "STANDARD"})
+
+	opt := s.options("bucket",
+		Options{ContentType: "text/plain"},
+		WithContentType("image/webp"),
+		WithMetadata(map[string]string{"a": "1"}),
+		WithMetadata(map[string]string{"b": "2"}),
+		WithStorageClass("STANDARD_IA"),
+		WithChecksum(ChecksumSHA256),
+	)
+
+	if opt.ContentType != "image/webp" || opt.CacheControl != "no-cache" || opt.StorageClass != "STANDARD_IA" ||
+		opt.Checksum != ChecksumSHA256 || opt.Metadata["a"] != "1" || opt.Metadata["b"] != "2" {
+		t.Errorf("%+v", opt)
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	var last, total int64
+	option := WithProgress(func(transferred, size int64) { last, total = transferred, size })
+
+	var opt Options
+	option.applyUpload(&opt)
+	r := &progressReader{r: bytes.NewReader(make([]byte, 100)), p: &progress{total: 100, fn: opt.progress}}
+	if _, err := io.Copy(io.Discard, r); err != nil || last != 100 || total != 100 {
+		t.Error(last, total, err)
+	}
+
+	download := newDownloadOptions([]DownloadOption{option})
+	w := &progressWriter{w: io.Discard, p: &progress{total: -1, fn: download.progress}}
+	w.Write(make([]byte, 30))
+	w.Write(make([]byte, 20))
+	if last != 50 || total != -1 {
+		t.Error(last, total)
+	}
+}
+
+func TestTransferOptions(t *testing.T) {
+	s := &Storage{profiles: &profiles{}, config: Config{PartSize: 16 << 20, Concurrency: 8, MaxUploadParts: 500}}
+
+	var u manager.Uploader
+	s.config.uploaderOptions(&u)
+	s.options("bucket", WithConcurrency(2)).uploaderOptions(&u)
+	if u.PartSize != 16<<20 || u.Concurrency != 2 || u.MaxUploadParts != 500 {
+		t.Errorf("%+v", u)
+	}
+
+	var d manager.Downloader
+	s.config.downloaderOptions(&d)
+	newDownloadOptions([]DownloadOption{WithPartSize(64 << 20)}).downloaderOptions(&d)
+	if d.PartSize != 64<<20 || d.Concurrency != 8 {
+		t.Errorf("%+v", d)
+	}
+}
+
+func TestProgressReaderAt(t *testing.T) {
+	var last int64
+	section := io.NewSectionReader(bytes.NewReader(make([]byte, 100)), 0, 100)
+	r := &progressReaderAt{readerAtSeeker: section, p: &progress{total: 100, fn: func(n, _ int64) { last = n }}}
+
+	// manager 가 part 별로 만드는 것과 같은 section
+	if _, err := io.Copy(io.Discard, io.NewSectionReader(r, 60, 40)); err != nil || last != 40 {
+		t.Error(last, err)
+	}
+	if _, err := io.Copy(io.Discard, io.NewSectionReader(r, 0, 60)); err != nil || last != 100 {
+		t.Error(last, err)
+	}
+}
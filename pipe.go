@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Pipe 는 src 의 객체를 dst 로 스트리밍하여 옮긴다. src 와 dst 는 서로 다른 스토리지(계정, 엔드포인트)여도 된다.
+// 객체 전체를 메모리나 디스크에 두지 않고 dst 의 multipart 업로드로 보내므로
+// 메모리 사용량은 part 크기 × 동시 업로드 수 정도이다.
+// 헤더와 메타데이터는 원본을 따르며, options 를 지정하면 그 값만 바꾼다. storage class 는 스토리지마다 달라 옮기지 않는다.
+// 원본이 도중에 끊겨 Content-Length 보다 짧게 읽히면 io.ErrUnexpectedEOF 로 실패한다.
+// 크기가 0 인 객체는 UploadReader 와 같이 거부된다.
+func Pipe(src *Storage, srcBucket, srcKey string, dst *Storage, dstBucket, dstKey string, options ...UploadOption) error {
+	output, err := src.getObject(srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	defer output.Body.Close()
+
+	headers := Options{
+		ContentType:        aws.ToString(output.ContentType),
+		CacheControl:       aws.ToString(output.CacheControl),
+		ContentDisposition: aws.ToString(output.ContentDisposition),
+		ContentEncoding:    aws.ToString(output.ContentEncoding),
+		ContentLanguage:    aws.ToString(output.ContentLanguage),
+		Metadata:           output.Metadata,
+	}
+
+	body := &exactReader{r: output.Body, remaining: aws.ToInt64(output.ContentLength)}
+	if err = dst.UploadReader(dstBucket, dstKey, body, append([]UploadOption{headers}, options...)...); err != nil {
+		return fmt.Errorf("pipe %s/%s: %w", srcBucket, srcKey, err)
+	}
+	return nil
+}
+
+// exactReader 는 remaining byte 를 모두 읽기 전에 EOF 가 오면 io.ErrUnexpectedEOF 를 반환한다.
+type exactReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (e *exactReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+	if err == io.EOF && e.remaining > 0 {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExactReader(t *testing.T) {
+	data, err := io.ReadAll(&exactReader{r: strings.NewReader("hello"), remaining: 5})
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+
+	_, err = io.ReadAll(&exactReader{r: strings.NewReader("hel"), remaining: 5})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected ErrUnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrNotPlaced = errors.New("object not found in any route")
+
+// PlacementIndex 는 key 가 저장된 route 이름을 기록한다.
+// 여러 프로세스가 공유하려면 DB, Redis 등으로 구현한다.
+type PlacementIndex interface {
+	Set(key, route string) error
+	Get(key string) (route string, ok bool, err error)
+}
+
+type memoryIndex struct {
+	mu     sync.RWMutex
+	routes map[string]string
+}
+
+// NewMemoryIndex 프로세스 내 메모리 index
+func NewMemoryIndex() PlacementIndex {
+	return &memoryIndex{routes: map[string]string{}}
+}
+
+func (m *memoryIndex) Set(key, route string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[key] = route
+	return nil
+}
+
+func (m *memoryIndex) Get(key string) (string, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	route, ok := m.routes[key]
+	return route, ok, nil
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrNotAllowed = errors.New("operation not allowed by policy")
+
+// Operation 은 Policy 에서 허용 여부를 판단하는 작업 단위
+type Operation string
+
+const (
+	OpInfo   Operation = "info"
+	OpList   Operation = "list"
+	OpGet    Operation = "get" // Download, PresignGet
+	OpPut    Operation = "put" // Upload, PresignPut
+	OpDelete Operation = "delete"
+	OpBucket Operation = "bucket" // 버킷 설정 변경, key 는 "" 로 검사
+)
+
+// Policy 는 Storage 인스턴스가 수행할 수 있는 작업과 key prefix 를 제한한다.
+// 규칙 중 하나라도 일치하면 허용된다.
+type Policy struct {
+	Rules []Rule
+}
+
+type Rule struct {
+	Operations []Operation // 비어 있으면 모든 작업
+	Prefixes   []string    // 비어 있으면 모든 key
+}
+
+func (p *Policy) Allow(op Operation, key string) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, rule := range p.Rules {
+		if len(rule.Operations) > 0 && !slices.Contains(rule.Operations, op) {
+			continue
+		}
+
+		if len(rule.Prefixes) == 0 {
+			return nil
+		}
+
+		for _, prefix := range rule.Prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %s %q", ErrNotAllowed, op, key)
+}
@@ -0,0 +1,36 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := &storage.Policy{Rules: []storage.Rule{
+		{Operations: []storage.Operation{storage.OpPut}, Prefixes: []string{"incoming/"}},
+		{Operations: []storage.Operation{storage.OpGet, storage.OpInfo}},
+	}}
+
+	if err := policy.Allow(storage.OpPut, "incoming/a.jpg"); err != nil {
+		t.Error("incoming/ 업로드가 거부됨:", err)
+	}
+
+	if err := policy.Allow(storage.OpPut, "other/a.jpg"); !errors.Is(err, storage.ErrNotAllowed) {
+		t.Error("prefix 밖 업로드가 허용됨:", err)
+	}
+
+	if err := policy.Allow(storage.OpGet, "other/a.jpg"); err != nil {
+		t.Error("다운로드가 거부됨:", err)
+	}
+
+	if err := policy.Allow(storage.OpDelete, "incoming/a.jpg"); !errors.Is(err, storage.ErrNotAllowed) {
+		t.Error("삭제가 허용됨:", err)
+	}
+
+	var none *storage.Policy
+	if err := none.Allow(storage.OpDelete, "any"); err != nil {
+		t.Error("nil 정책은 모두 허용해야 함:", err)
+	}
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// PostConditions 는 브라우저 form 업로드에 거는 제약. 0 값 필드는 검사하지 않는다.
+type PostConditions struct {
+	MinSize           int64             // byte
+	MaxSize           int64             // byte, 0 이면 제한 없음
+	ContentType       string            // 정확히 일치해야 함, Fields 에 포함된다.
+	ContentTypePrefix string            // 예: "image/", 클라이언트가 Content-Type 필드를 보내야 한다.
+	Metadata          map[string]string // x-amz-meta-*, Fields 에 포함된다.
+}
+
+// PresignedPost 는 form 을 보낼 URL 과 함께 보내야 하는 필드. 파일은 마지막 "file" 필드로 보낸다.
+type PresignedPost struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
+// PresignPost 는 ttl 동안 유효한 POST Object form 을 발급한다. PresignPut 과 달리 크기 범위와 Content-Type 을 서명에 포함할 수 있다.
+// ttl 은 PresignGet 과 같이 검사하며, POST Object 를 지원하지 않는 스토리지(R2, B2)이면 *CapabilityError 를 반환한다.
+func (s *Storage) PresignPost(bucket, key string, ttl time.Duration, conditions PostConditions) (*PresignedPost, error) {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Supports(CapPresignPost) {
+		return nil, &CapabilityError{Type: s.Type(), Missing: []Capability{CapPresignPost}, Supported: capabilities[s.Type()]}
+	}
+
+	ttl, err = s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, fields, err := conditions.policy()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.presignClient.PresignPostObject(s.requestContext(), &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = ttl
+		o.Conditions = policy
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maps.Copy(fields, res.Values)
+	return &PresignedPost{URL: res.URL, Fields: fields}, nil
+}
+
+// policy 는 POST policy 조건과 form 에 고정으로 넣을 필드
+func (c PostConditions) policy() ([]any, map[string]string, error) {
+	if c.MinSize < 0 || (c.MaxSize > 0 && c.MinSize > c.MaxSize) {
+		return nil, nil, fmt.Errorf("invalid size range %d-%d", c.MinSize, c.MaxSize)
+	}
+
+	var (
+		policy []any
+		fields = map[string]string{}
+	)
+
+	if c.MaxSize > 0 {
+		policy = append(policy, []any{"content-length-range", c.MinSize, c.MaxSize})
+	} else if c.MinSize > 0 {
+		policy = append(policy, []any{"content-length-range", c.MinSize, providerLimits[AWS].MaxObjectSize})
+	}
+
+	if c.ContentType != "" {
+		policy = append(policy, map[string]string{"Content-Type": c.ContentType})
+		fields["Content-Type"] = c.ContentType
+	} else if c.ContentTypePrefix != "" {
+		policy = append(policy, []any{"starts-with", "$Content-Type", c.ContentTypePrefix})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Metadata)) {
+		field := "x-amz-meta-" + name
+		policy = append(policy, map[string]string{field: c.Metadata[name]})
+		fields[field] = c.Metadata[name]
+	}
+
+	return policy, fields, nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPostConditionsPolicy(t *testing.T) {
+	policy, fields, err := PostConditions{
+		MaxSize:     10 << 20,
+		ContentType: "image/png",
+		Metadata:    map[string]string{"user": "42"},
+	}.policy()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{
+		[]any{"content-length-range", int64(0), int64(10 << 20)},
+		map[string]string{"Content-Type": "image/png"},
+		map[string]string{"x-amz-meta-user": "42"},
+	}
+	if !reflect.DeepEqual(policy, want) {
+		t.Errorf("%v", policy)
+	}
+	if fields["Content-Type"] != "image/png" || fields["x-amz-meta-user"] != "42" {
+		t.Errorf("%v", fields)
+	}
+
+	policy, fields, _ = PostConditions{ContentTypePrefix: "image/"}.policy()
+	if !reflect.DeepEqual(policy, []any{[]any{"starts-with", "$Content-Type", "image/"}}) || len(fields) != 0 {
+		t.Errorf("%v %v", policy, fields)
+	}
+
+	if _, _, err = (PostConditions{MinSize: 10, MaxSize: 5}).policy(); err == nil {
+		t.Error("잘못된 크기 범위가 허용됨")
+	}
+}
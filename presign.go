@@ -0,0 +1,56 @@
+package storage
+
+import "mime"
+
+// PresignOption 은 PresignGet, PresignPut 으로 발급하는 URL 에 서명할 헤더를 정한다.
+type PresignOption interface {
+	applyPresign(*presignOptions)
+}
+
+type presignOptionFunc func(*presignOptions)
+
+func (fn presignOptionFunc) applyPresign(o *presignOptions) {
+	fn(o)
+}
+
+type presignOptions struct {
+	responseContentType        string // GET
+	responseContentDisposition string // GET
+	contentType                string // PUT
+}
+
+func newPresignOptions(options []PresignOption) *presignOptions {
+	opt := &presignOptions{}
+	for _, option := range options {
+		option.applyPresign(opt)
+	}
+	return opt
+}
+
+// WithResponseContentType 은 GET URL 응답의 Content-Type 을 바꾼다 (response-content-type). PresignPut 에서는 무시.
+func WithResponseContentType(contentType string) PresignOption {
+	return presignOptionFunc(func(o *presignOptions) {
+		o.responseContentType = contentType
+	})
+}
+
+// WithResponseContentDisposition 은 GET URL 응답의 Content-Disposition 을 바꾼다 (response-content-disposition).
+// 브라우저에서 바로 열지(inline), 파일 이름을 지정해 저장할지(AttachmentDisposition) 정할 때 사용한다. PresignPut 에서는 무시.
+func WithResponseContentDisposition(disposition string) PresignOption {
+	return presignOptionFunc(func(o *presignOptions) {
+		o.responseContentDisposition = disposition
+	})
+}
+
+// WithUploadContentType 은 PUT URL 에 Content-Type 을 서명에 포함한다. 클라이언트가 다른 Content-Type 으로 올리면 거부된다.
+// PresignGet 에서는 무시.
+func WithUploadContentType(contentType string) PresignOption {
+	return presignOptionFunc(func(o *presignOptions) {
+		o.contentType = contentType
+	})
+}
+
+// AttachmentDisposition 은 filename 으로 저장하게 하는 Content-Disposition 값. ASCII 가 아닌 이름은 RFC 2231 로 인코딩한다.
+func AttachmentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
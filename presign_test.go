@@ -0,0 +1,27 @@
+package storage
+
+import "testing"
+
+func TestPresignOptions(t *testing.T) {
+	opt := newPresignOptions([]PresignOption{
+		WithResponseContentType("application/pdf"),
+		WithResponseContentDisposition("inline"),
+		WithUploadContentType("image/png"),
+	})
+	if opt.responseContentType != "application/pdf" || opt.responseContentDisposition != "inline" || opt.contentType != "image/png" {
+		t.Errorf("%+v", opt)
+	}
+}
+
+func TestAttachmentDisposition(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":  `attachment; filename=report.pdf`,
+		"my file.pdf": `attachment; filename="my file.pdf"`,
+		"보고서.pdf":     `attachment; filename*=utf-8''%EB%B3%B4%EA%B3%A0%EC%84%9C.pdf`,
+	}
+	for name, want := range cases {
+		if got := AttachmentDisposition(name); got != want {
+			t.Errorf("%s: %s", name, got)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var ErrMissingPart = errors.New("multipart upload is missing parts")
+
+// PresignedPart 는 클라이언트가 직접 PUT 할 part 하나
+type PresignedPart struct {
+	Number int32  `json:"number"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	URL    string `json:"url"`
+}
+
+// PresignUploadParts 는 CreateMultipart 로 시작한 업로드의 size byte 를 나눈 part 마다 ttl 동안 유효한 PUT URL 을 발급한다.
+// 브라우저나 모바일 클라이언트가 큰 파일을 서버를 거치지 않고 part 단위로 바로 올릴 때 사용한다.
+// part 수가 10000 을 넘으면 state.PartSize 를 늘리고 state.Size 에 size 를 기록하므로, 발급 후 state 를 저장해야 한다.
+// ttl 은 PresignGet 과 같이 검사한다. 클라이언트가 모두 올리면 CompletePresignedMultipart 를 호출한다.
+func (s *Storage) PresignUploadParts(state *UploadState, size int64, ttl time.Duration) ([]PresignedPart, error) {
+	if size <= 0 {
+		return nil, errors.New("zero size file")
+	}
+
+	ttl, err := s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	state.PartSize = presignPartSize(size, state.PartSize)
+	state.Size = size
+
+	var parts []PresignedPart
+	for offset, number := int64(0), int32(1); offset < size; offset, number = offset+state.PartSize, number+1 {
+		length := min(state.PartSize, size-offset)
+		res, err := s.presignClient.PresignUploadPart(s.requestContext(), &s3.UploadPartInput{
+			Bucket:        aws.String(state.Bucket),
+			Key:           aws.String(state.Key),
+			UploadId:      aws.String(state.UploadID),
+			PartNumber:    aws.Int32(number),
+			ContentLength: aws.Int64(length),
+		}, s3.WithPresignExpires(ttl))
+		if err != nil {
+			return nil, err
+		}
+
+		parts = append(parts, PresignedPart{Number: number, Offset: offset, Size: length, URL: res.URL})
+	}
+	return parts, nil
+}
+
+// presignPartSize 는 최소 크기(5 MiB) 이상이면서 part 수가 10000 을 넘지 않는 part 크기
+func presignPartSize(size, partSize int64) int64 {
+	if partSize < minPartSize {
+		partSize = defaultPartSize
+	}
+	if size > partSize*maxPartNumber {
+		partSize = (size + maxPartNumber - 1) / maxPartNumber
+	}
+	return partSize
+}
+
+// CompletePresignedMultipart 는 클라이언트가 올린 part 를 ListParts 로 확인해 state 에 기록하고 객체를 만든다.
+// 클라이언트가 ETag 를 따로 보고하지 않아도 된다.
+// PresignUploadParts 가 기록한 state.Size 의 모든 part 가 발급한 크기 그대로 올라오지 않았으면
+// 완료하지 않고 ErrMissingPart 를 반환한다 (뒷부분이 잘린 객체가 만들어지지 않도록).
+func (s *Storage) CompletePresignedMultipart(state *UploadState) error {
+	parts, err := s.ListParts(state.Bucket, state.Key, state.UploadID)
+	if err != nil {
+		return err
+	}
+
+	if err = checkPresignedParts(state, parts); err != nil {
+		return err
+	}
+
+	var offset int64
+	for _, part := range parts {
+		state.addPart(UploadedPart{
+			Number: part.Number,
+			ETag:   `"` + strings.Trim(part.ETag, `"`) + `"`,
+			Offset: offset,
+			Size:   part.Size,
+		})
+		offset += part.Size
+	}
+
+	return s.CompleteMultipart(state)
+}
+
+// checkPresignedParts 는 parts 가 state.Size 를 PartSize 로 나눈 1..N 번 part 와 크기가 같은지 확인한다.
+func checkPresignedParts(state *UploadState, parts []PartInfo) error {
+	if state.Size <= 0 || state.PartSize <= 0 {
+		return fmt.Errorf("%w: upload size not recorded, use PresignUploadParts", ErrMissingPart)
+	}
+
+	count := int((state.Size + state.PartSize - 1) / state.PartSize)
+	for i := range count {
+		number := int32(i + 1)
+		if i >= len(parts) || parts[i].Number != number {
+			return fmt.Errorf("%w: part %d of %d", ErrMissingPart, number, count)
+		}
+
+		offset := int64(i) * state.PartSize
+		if want := min(state.PartSize, state.Size-offset); parts[i].Size != want {
+			return fmt.Errorf("%w: part %d is %d bytes, expected %d", ErrMissingPart, number, parts[i].Size, want)
+		}
+	}
+
+	if len(parts) > count {
+		return fmt.Errorf("%w: unexpected part %d of %d", ErrMissingPart, parts[count].Number, count)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestPresignPartSize(t *testing.T) {
+	cases := []struct{ size, partSize, want int64 }{
+		{100 << 20, 0, defaultPartSize},
+		{100 << 20, 1 << 20, defaultPartSize}, // 최소 크기 미만
+		{100 << 20, 16 << 20, 16 << 20},
+		{200 << 30, 0, (200<<30 + maxPartNumber - 1) / maxPartNumber}, // 10000 개 초과
+	}
+	for _, c := range cases {
+		got := presignPartSize(c.size, c.partSize)
+		if got != c.want || (c.size+got-1)/got > maxPartNumber {
+			t.Errorf("presignPartSize(%d, %d) = %d", c.size, c.partSize, got)
+		}
+	}
+}
+
+func TestCheckPresignedParts(t *testing.T) {
+	state := &UploadState{PartSize: 10, Size: 25}
+	full := []PartInfo{{Number: 1, Size: 10}, {Number: 2, Size: 10}, {Number: 3, Size: 5}}
+
+	if err := checkPresignedParts(state, full); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := [][]PartInfo{
+		full[:2], // 뒷부분 part 가 없음
+		nil,
+		{full[0], full[2]},
+		{full[0], full[1], {Number: 3, Size: 4}},
+		append(full, PartInfo{Number: 4, Size: 1}),
+	}
+	for i, parts := range cases {
+		if err := checkPresignedParts(state, parts); !errors.Is(err, ErrMissingPart) {
+			t.Errorf("case %d: expected ErrMissingPart, got %v", i, err)
+		}
+	}
+
+	if err := checkPresignedParts(&UploadState{PartSize: 10}, full); !errors.Is(err, ErrMissingPart) {
+		t.Errorf("unknown size: got %v", err)
+	}
+}
+
+func TestCompletePresignedMultipartTruncated(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	state, err := store.CreateMultipart("bucket", "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(defaultPartSize + 1<<20)
+	if _, err = store.PresignUploadParts(state, size, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// 클라이언트가 1번 part 만 올리고 멈춤
+	_, err = store.client.UploadPart(context.Background(), &s3.UploadPartInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("big.bin"),
+		UploadId:   aws.String(state.UploadID),
+		PartNumber: aws.Int32(1),
+		Body:       bytes.NewReader(make([]byte, state.PartSize)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = store.CompletePresignedMultipart(state); !errors.Is(err, ErrMissingPart) {
+		t.Fatalf("expected ErrMissingPart, got %v", err)
+	}
+	if fake.get("bucket", "big.bin") != nil {
+		t.Fatal("truncated object created")
+	}
+}
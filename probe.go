@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 미디어 정보 메타데이터 key
+const (
+	metaWidth    = "width"
+	metaHeight   = "height"
+	metaDuration = "duration" // 초, 예: 12.5
+)
+
+// MediaInfo 는 이미지/영상의 크기와 길이. 알 수 없는 값은 0.
+type MediaInfo struct {
+	Width    int
+	Height   int
+	Duration time.Duration
+}
+
+// Prober 는 업로드할 파일에서 MediaInfo 를 읽는다.
+// 영상은 ffprobe 등을 감싼 구현을 Config.Prober 에 넣는다.
+type Prober interface {
+	Probe(r io.ReaderAt, size int64, contentType string) (MediaInfo, error)
+}
+
+// ImageProber 는 표준 라이브러리로 gif, jpeg, png 의 크기를 읽는다.
+type ImageProber struct{}
+
+func (ImageProber) Probe(r io.ReaderAt, size int64, contentType string) (MediaInfo, error) {
+	if !strings.HasPrefix(contentType, "image/") {
+		return MediaInfo{}, nil
+	}
+
+	config, _, err := image.DecodeConfig(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return MediaInfo{}, err
+	}
+	return MediaInfo{Width: config.Width, Height: config.Height}, nil
+}
+
+// MediaInfoFromMetadata 는 Info 등으로 받은 메타데이터에서 MediaInfo 를 읽는다.
+func MediaInfoFromMetadata(metadata map[string]string) MediaInfo {
+	var info MediaInfo
+	info.Width, _ = strconv.Atoi(metadata[metaWidth])
+	info.Height, _ = strconv.Atoi(metadata[metaHeight])
+	if seconds, err := strconv.ParseFloat(metadata[metaDuration], 64); err == nil {
+		info.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	return info
+}
+
+func (m MediaInfo) metadata() map[string]string {
+	metadata := map[string]string{}
+	if m.Width > 0 && m.Height > 0 {
+		metadata[metaWidth] = strconv.Itoa(m.Width)
+		metadata[metaHeight] = strconv.Itoa(m.Height)
+	}
+	if m.Duration > 0 {
+		metadata[metaDuration] = strconv.FormatFloat(m.Duration.Seconds(), 'f', -1, 64)
+	}
+	return metadata
+}
+
+// probeMetadata Config.Prober 로 읽은 미디어 정보 메타데이터
+// 판별 실패는 업로드를 막지 않도록 무시한다.
+func (s *Storage) probeMetadata(r io.ReaderAt, size int64, contentType string) map[string]string {
+	if s.config.Prober == nil {
+		return nil
+	}
+
+	info, err := s.config.Prober.Probe(r, size, contentType)
+	if err != nil {
+		return nil
+	}
+	return info.metadata()
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestImageProber(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ImageProber{}.Probe(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "image/png")
+	if err != nil || info.Width != 40 || info.Height != 30 {
+		t.Fatal(info, err)
+	}
+
+	if info, err = (ImageProber{}).Probe(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "video/mp4"); err != nil || info != (MediaInfo{}) {
+		t.Error("이미지가 아니면 건너뛰어야 함:", info, err)
+	}
+}
+
+func TestMediaInfoMetadata(t *testing.T) {
+	info := MediaInfo{Width: 1920, Height: 1080, Duration: 12500 * time.Millisecond}
+	metadata := info.metadata()
+	if metadata[metaDuration] != "12.5" {
+		t.Error(metadata)
+	}
+	if got := MediaInfoFromMetadata(metadata); got != info {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"maps"
+	"sync"
+)
+
+type profiles struct {
+	mu     sync.RWMutex
+	bucket map[string]Options
+}
+
+// SetProfile 은 bucket 에 업로드할 때 기본으로 적용할 옵션을 등록한다.
+// 호출 시 지정한 옵션이 profile 보다 우선한다.
+func (s *Storage) SetProfile(bucket string, profile Options) {
+	s.profiles.mu.Lock()
+	defer s.profiles.mu.Unlock()
+
+	if s.profiles.bucket == nil {
+		s.profiles.bucket = map[string]Options{}
+	}
+	s.profiles.bucket[bucket] = profile
+}
+
+// options 는 bucket profile 에 호출 옵션을 순서대로 적용한 사본을 반환한다.
+func (s *Storage) options(bucket string, options ...UploadOption) *Options {
+	s.profiles.mu.RLock()
+	opt := s.profiles.bucket[bucket]
+	s.profiles.mu.RUnlock()
+
+	opt.Headers = maps.Clone(opt.Headers)
+	opt.Metadata = maps.Clone(opt.Metadata)
+	for _, option := range options {
+		option.applyUpload(&opt)
+	}
+
+	return &opt
+}
+
+// applyUpload 는 call 에서 지정한 필드만 opt 에 덮어쓴다.
+func (call Options) applyUpload(opt *Options) {
+	for key, value := range call.Headers {
+		if opt.Headers == nil {
+			opt.Headers = map[string]string{}
+		}
+		opt.Headers[key] = value
+	}
+
+	if call.ContentType != "" {
+		opt.ContentType = call.ContentType
+	}
+	if call.Checksum != "" {
+		opt.Checksum = call.Checksum
+	}
+	if call.CacheControl != "" {
+		opt.CacheControl = call.CacheControl
+	}
+	if call.StorageClass != "" {
+		opt.StorageClass = call.StorageClass
+	}
+	if !call.ExpiresAt.IsZero() {
+		opt.ExpiresAt = call.ExpiresAt
+	}
+	if call.PartSize > 0 {
+		opt.PartSize = call.PartSize
+	}
+	if call.Concurrency > 0 {
+		opt.Concurrency = call.Concurrency
+	}
+	if call.ContentDisposition != "" {
+		opt.ContentDisposition = call.ContentDisposition
+	}
+	if call.ContentEncoding != "" {
+		opt.ContentEncoding = call.ContentEncoding
+	}
+	if call.ContentLanguage != "" {
+		opt.ContentLanguage = call.ContentLanguage
+	}
+
+	for key, value := range call.Metadata {
+		if opt.Metadata == nil {
+			opt.Metadata = map[string]string{}
+		}
+		opt.Metadata[key] = value
+	}
+
+	if call.progress != nil {
+		opt.progress = call.progress
+	}
+}
@@ -0,0 +1,23 @@
+package storage
+
+import "testing"
+
+func TestOptionsMetadata(t *testing.T) {
+	s := &Storage{config: Config{CreatedBy: "svc"}, profiles: &profiles{}}
+	s.SetProfile("bucket", Options{Metadata: map[string]string{"Team": "media", "tier": "hot"}, ContentLanguage: "ko"})
+
+	opt := s.options("bucket", Options{Metadata: map[string]string{"tier": "cold"}, ContentDisposition: "attachment"})
+	if opt.ContentLanguage != "ko" || opt.ContentDisposition != "attachment" {
+		t.Errorf("%+v", opt)
+	}
+
+	metadata := s.objectMetadata(opt)
+	if metadata["team"] != "media" || metadata["tier"] != "cold" || metadata[metaCreatedBy] != "svc" {
+		t.Error(metadata)
+	}
+
+	// 호출 옵션이 profile 을 바꾸지 않아야 함
+	if s.options("bucket").Metadata["tier"] != "hot" {
+		t.Error("profile metadata 가 바뀜")
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import "strings"
+
+const (
+	AWS   SType = "s3"
+	R2    SType = "r2"
+	B2    SType = "b2"
+	Other SType = "s3-compatible"
+)
+
+// Type 은 Endpoint 로 판단한 스토리지 종류
+func (s *Storage) Type() SType {
+	return detectType(s.config.Endpoint)
+}
+
+func detectType(endpoint string) SType {
+	switch {
+	case strings.Contains(endpoint, "r2.cloudflarestorage.com"):
+		return R2
+	case strings.Contains(endpoint, "backblazeb2.com"):
+		return B2
+	case strings.Contains(endpoint, "amazonaws.com"):
+		return AWS
+	default:
+		return Other
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PublicObject 는 공개 / CDN URL 로 확인한 객체 정보
+type PublicObject struct {
+	URL          string
+	Status       int
+	Size         int64 // Content-Length, 모르면 -1
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+	CacheStatus  string // CDN 캐시 상태 헤더 (CF-Cache-Status, X-Cache 등), 없으면 ""
+}
+
+// PublicInfo 는 인증 없이 공개 URL 에 HEAD 요청을 보내 객체 정보를 확인한다.
+// 배포한 파일을 외부에서 모니터링하는 용도로, 연결 오류와 5xx 는 최대 3번 재시도한다.
+// 응답 상태가 200 이 아니어도 오류 없이 Status 로 반환한다.
+// 재시도 대기 중 WithContext 의 ctx 가 끝나면 기다리지 않고 ctx 의 오류를 반환한다.
+func (s *Storage) PublicInfo(url string) (*PublicObject, error) {
+	var (
+		ctx     = s.requestContext()
+		resp    *http.Response
+		err     error
+		backoff = 200 * time.Millisecond
+	)
+
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		resp, err = s.httpClient.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < http.StatusInternalServerError {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	object := &PublicObject{
+		URL:         url,
+		Status:      resp.StatusCode,
+		Size:        resp.ContentLength,
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	object.LastModified, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	for _, name := range []string{"CF-Cache-Status", "CDN-Cache", "X-Cache"} {
+		if value := resp.Header.Get(name); value != "" {
+			object.CacheStatus = value
+			break
+		}
+	}
+
+	return object, nil
+}
+
+// PublicExists 공개 URL 의 객체 존재 여부
+// 200 이면 true, 404 / 410 이면 false, 그 밖의 상태는 오류.
+func (s *Storage) PublicExists(url string) (bool, error) {
+	object, err := s.PublicInfo(url)
+	if err != nil {
+		return false, err
+	}
+
+	switch object.Status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	}
+	return false, fmt.Errorf("public check failed: %s %d", url, object.Status)
+}
+
+// PublicURL 은 key 의 공개 URL. Config.PublicBaseURL(CDN, R2 공개 도메인, Bunny pull zone 등)이 있으면 그 아래,
+// 없으면 스토리지 기본 주소(S3 virtual-hosted, B2 f00x 다운로드 주소, 기타 path-style)로 만든다.
+// R2 는 공개 도메인을 Endpoint 로 알 수 없으므로 PublicBaseURL 이 없으면 ErrNoPublicURL.
+// 버킷이 공개되어 있는지는 확인하지 않는다 (PublicExists 로 확인).
+func (s *Storage) PublicURL(bucket, key string) (string, error) {
+	if s.config.PublicBaseURL != "" {
+		base := strings.ReplaceAll(s.config.PublicBaseURL, "{bucket}", bucket)
+		return strings.TrimSuffix(base, "/") + "/" + escapeKey(key), nil
+	}
+
+	base, err := providerPublicURL(s.Type(), s.config.Endpoint, s.config.Region, bucket)
+	if err != nil {
+		return "", err
+	}
+	return base + "/" + escapeKey(key), nil
+}
+
+// providerPublicURL 은 스토리지 기본 공개 주소 (끝에 / 없음)
+func providerPublicURL(stype SType, endpoint, region, bucket string) (string, error) {
+	switch stype {
+	case AWS:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region), nil
+	case B2:
+		// s3.us-west-004.backblazeb2.com → f004.backblazeb2.com
+		cluster := region[strings.LastIndex(region, "-")+1:]
+		if cluster == "" {
+			return "", ErrNoPublicURL
+		}
+		return fmt.Sprintf("https://f%s.backblazeb2.com/file/%s", cluster, bucket), nil
+	case R2:
+		return "", ErrNoPublicURL
+	default:
+		return strings.TrimSuffix(endpoint, "/") + "/" + bucket, nil
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProviderPublicURL(t *testing.T) {
+	cases := []struct {
+		stype            SType
+		endpoint, region string
+		want             string
+	}{
+		{AWS, "https://s3.ap-northeast-2.amazonaws.com", "ap-northeast-2", "https://assets.s3.ap-northeast-2.amazonaws.com"},
+		{B2, "https://s3.us-west-004.backblazeb2.com", "us-west-004", "https://f004.backblazeb2.com/file/assets"},
+		{Other, "http://minio:9000/", "auto", "http://minio:9000/assets"},
+	}
+	for _, c := range cases {
+		got, err := providerPublicURL(c.stype, c.endpoint, c.region, "assets")
+		if err != nil || got != c.want {
+			t.Errorf("%s: %s %v", c.stype, got, err)
+		}
+	}
+
+	if _, err := providerPublicURL(R2, "https://x.r2.cloudflarestorage.com", "auto", "assets"); !errors.Is(err, ErrNoPublicURL) {
+		t.Error(err)
+	}
+}
+
+func TestPublicURLBase(t *testing.T) {
+	s := &Storage{config: Config{PublicBaseURL: "https://cdn.example.com/{bucket}/"}}
+	got, err := s.PublicURL("assets", "img/a b.png")
+	if err != nil || got != "https://cdn.example.com/assets/img/a%20b.png" {
+		t.Error(got, err)
+	}
+}
+
+func TestPublicInfoRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("CF-Cache-Status", "HIT")
+		w.Header().Set("Content-Length", "5")
+	}))
+	defer server.Close()
+
+	s := &Storage{httpClient: http.DefaultClient}
+	info, err := s.PublicInfo(server.URL + "/a.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 3 || info.Status != http.StatusOK || info.ETag != "abc" || info.Size != 5 || info.CacheStatus != "HIT" {
+		t.Errorf("requests %d, %+v", requests.Load(), info)
+	}
+
+	// 재시도 대기 중 ctx 가 끝나면 바로 반환
+	requests.Store(-10)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.WithContext(ctx).PublicInfo(server.URL + "/a.js"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("canceled: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("canceled after %s", elapsed)
+	}
+}
+
+func TestPublicExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/private":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	s := &Storage{httpClient: http.DefaultClient}
+	cases := map[string]bool{"/ok": true, "/missing": false, "/gone": false}
+	for path, want := range cases {
+		if ok, err := s.PublicExists(server.URL + path); err != nil || ok != want {
+			t.Errorf("%s: %v, %v", path, ok, err)
+		}
+	}
+	if _, err := s.PublicExists(server.URL + "/private"); err == nil {
+		t.Error("403 을 오류로 반환하지 않음")
+	}
+}
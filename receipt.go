@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var (
+	ErrTicketExpired  = errors.New("object uploaded after ticket expired")
+	ErrNotUploaded    = errors.New("object not uploaded with ticket")
+	ErrInvalidReceipt = errors.New("invalid receipt signature")
+)
+
+// UploadTicket 은 클라이언트에게 발급한 presigned PUT 과 기대값.
+// 앱이 저장해 두었다가 클라이언트가 업로드 완료를 알리면 VerifyAndReceipt 에 넘긴다.
+type UploadTicket struct {
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	Size      int64     `json:"size,omitempty"`   // 0 이면 검사하지 않음
+	SHA256    string    `json:"sha256,omitempty"` // 비어 있으면 검사하지 않음
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Receipt 는 업로드된 객체를 확인했다는 서명된 증명
+type Receipt struct {
+	Bucket     string    `json:"bucket"`
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256"`
+	UploadedAt time.Time `json:"uploaded_at"` // 객체 Last-Modified
+	VerifiedAt time.Time `json:"verified_at"`
+	Signature  []byte    `json:"signature,omitempty"` // ed25519, Signature 를 뺀 JSON 에 대한 서명
+}
+
+// IssueUploadTicket 은 ttl 동안 유효한 presigned PUT URL 과 기대값(크기, SHA-256)을 담은 ticket 을 발급한다.
+// ttl 은 Config.PresignTTL 로 조정된 값이 ExpiresAt 에 반영된다.
+func (s *Storage) IssueUploadTicket(bucket, key string, ttl time.Duration, expected ...Artifact) (*UploadTicket, error) {
+	ttl, err := s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.PresignPut(bucket, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	ticket := &UploadTicket{
+		Bucket:    bucket,
+		Key:       key,
+		URL:       url,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if len(expected) > 0 {
+		ticket.Size = expected[0].Size
+		ticket.SHA256 = expected[0].SHA256
+	}
+	return ticket, nil
+}
+
+// VerifyAndReceipt 는 ticket 의 객체를 내려받아 크기와 SHA-256 을 확인하고 privateKey 로 서명한 Receipt 를 반환한다.
+// 기대값과 다르면 *MismatchError, ticket 만료 후 올라온(덮어쓴) 객체이면 ErrTicketExpired,
+// ticket 발급 전부터 있던 객체(클라이언트가 올리지 않음)이면 ErrNotUploaded.
+func (s *Storage) VerifyAndReceipt(ticket *UploadTicket, privateKey ed25519.PrivateKey) (*Receipt, error) {
+	output, err := s.getObject(ticket.Bucket, ticket.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	uploadedAt := aws.ToTime(output.LastModified)
+	if ticket.preexisting(uploadedAt) {
+		return nil, ErrNotUploaded
+	}
+	if ticket.expired(uploadedAt) {
+		return nil, ErrTicketExpired
+	}
+
+	artifact, err := hashReader(output.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := []DownloadOption{WithExpectedSHA256(ticket.SHA256)}
+	if ticket.Size > 0 {
+		expected = append(expected, WithExpectedSize(ticket.Size))
+	}
+	if err = newDownloadOptions(expected).verify(ticket.Key, artifact); err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{
+		Bucket:     ticket.Bucket,
+		Key:        ticket.Key,
+		Size:       artifact.Size,
+		SHA256:     artifact.SHA256,
+		UploadedAt: uploadedAt.UTC(),
+		VerifiedAt: time.Now().UTC(),
+	}
+
+	payload, err := receipt.payload()
+	if err != nil {
+		return nil, err
+	}
+	receipt.Signature = ed25519.Sign(privateKey, payload)
+	return receipt, nil
+}
+
+// expired 는 ticket 만료 후 올라온 객체인지 확인한다.
+func (t *UploadTicket) expired(uploadedAt time.Time) bool {
+	// Last-Modified 는 초 단위
+	return !t.ExpiresAt.IsZero() && uploadedAt.After(t.ExpiresAt.Add(time.Second))
+}
+
+// preexisting 은 ticket 발급 전부터 있던 객체인지 확인한다.
+func (t *UploadTicket) preexisting(uploadedAt time.Time) bool {
+	// Last-Modified 는 초 단위
+	return !t.IssuedAt.IsZero() && uploadedAt.Before(t.IssuedAt.Add(-time.Second))
+}
+
+// Verify 는 receipt 의 서명을 확인한다.
+func (r *Receipt) Verify(publicKey ed25519.PublicKey) error {
+	payload, err := r.payload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, r.Signature) {
+		return ErrInvalidReceipt
+	}
+	return nil
+}
+
+func (r *Receipt) payload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	return json.Marshal(unsigned)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReceiptVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt := &Receipt{
+		Bucket:     "bucket",
+		Key:        "uploads/a.jpg",
+		Size:       3,
+		SHA256:     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		UploadedAt: time.Now().UTC().Truncate(time.Second),
+		VerifiedAt: time.Now().UTC(),
+	}
+	payload, _ := receipt.payload()
+	receipt.Signature = ed25519.Sign(privateKey, payload)
+
+	// 저장했다가 다시 읽어도 검증되어야 함
+	data, _ := json.Marshal(receipt)
+	var stored Receipt
+	if err = json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err = stored.Verify(publicKey); err != nil {
+		t.Error(err)
+	}
+
+	stored.Size = 4
+	if err = stored.Verify(publicKey); !errors.Is(err, ErrInvalidReceipt) {
+		t.Error("변조된 receipt 가 검증됨:", err)
+	}
+}
+
+func TestVerifyAndReceiptPreexisting(t *testing.T) {
+	_, privateKey, _ := ed25519.GenerateKey(nil)
+	store, fake := newFakeStorage(t, Config{})
+
+	// 발급 전부터 있던 객체는 클라이언트가 올린 것이 아님
+	fake.put("bucket", "a.txt", []byte("abc"), time.Now().Add(-time.Hour))
+	ticket, err := store.IssueUploadTicket("bucket", "a.txt", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.VerifyAndReceipt(ticket, privateKey); !errors.Is(err, ErrNotUploaded) {
+		t.Fatalf("expected ErrNotUploaded, got %v", err)
+	}
+
+	fake.put("bucket", "a.txt", []byte("abc"))
+	receipt, err := store.VerifyAndReceipt(ticket, privateKey)
+	if err != nil || receipt.Size != 3 {
+		t.Fatalf("got %+v, %v", receipt, err)
+	}
+}
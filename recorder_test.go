@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	live, fake := newFakeStorage(t, Config{})
+	fake.put("bucket", "a.txt", []byte("hello"))
+
+	open := func(recorder *Recorder) *Storage {
+		config := live.config
+		config.Transport = recorder
+		store, err := New(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}
+
+	// 기록
+	recorder := NewRecorder(dir, Record)
+	recorder.Transport = live.config.Transport
+	store := open(recorder)
+	if err := store.putBytes("bucket", "b.txt", []byte("world"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"a.txt", "b.txt"} {
+		if _, err := store.DownloadBytes("bucket", key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 서명, 자격 증명은 fixture 에 남지 않음
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 3 {
+		t.Fatalf("fixtures %v", files)
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "Authorization") || strings.Contains(string(data), "X-Amz-Signature") {
+			t.Errorf("%s: credentials recorded", file)
+		}
+	}
+
+	// 재생, 네트워크를 쓰면 실패
+	fake.fail = func(*http.Request) int { return http.StatusInternalServerError }
+	store = open(NewRecorder(dir, Replay))
+	for key, want := range map[string]string{"a.txt": "hello", "b.txt": "world"} {
+		data, err := store.DownloadBytes("bucket", key)
+		if err != nil || string(data) != want {
+			t.Errorf("%s: %q, %v", key, data, err)
+		}
+	}
+
+	// 기록에 없는 요청
+	if _, err := store.DownloadBytes("bucket", "c.txt"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected missing fixture error, got %v", err)
+	}
+}
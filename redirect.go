@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUnauthenticated 를 RedirectHandler.Authorize 가 반환하면 401, 그 밖의 오류는 403 으로 응답한다.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// RedirectHandler 는 요청 경로를 key 로 보고, Authorize 를 통과하면 짧은 presigned GET URL 로 redirect 하는 http.Handler.
+// HEAD 요청은 presigned HEAD URL 로 redirect 한다.
+// 객체를 서버로 중계하지 않고 권한 확인만 한 뒤 스토리지에서 바로 받게 하는 용도.
+// 경로 앞부분은 http.StripPrefix 로 제거한다.
+type RedirectHandler struct {
+	TTL       time.Duration // presigned URL 유효 기간, 기본 1m
+	Authorize func(r *http.Request, key string) error
+
+	storage *Storage
+	bucket  string
+}
+
+func (s *Storage) RedirectHandler(bucket string, authorize func(r *http.Request, key string) error) *RedirectHandler {
+	return &RedirectHandler{
+		TTL:       time.Minute,
+		Authorize: authorize,
+		storage:   s,
+		bucket:    bucket,
+	}
+}
+
+func (h *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if key == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if h.Authorize == nil {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if err := h.Authorize(r, key); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, ErrUnauthenticated) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	presign := h.storage.PresignGet
+	if r.Method == http.MethodHead {
+		presign = func(bucket, key string, ttl time.Duration, _ ...PresignOption) (string, error) {
+			return h.storage.PresignHead(bucket, key, ttl)
+		}
+	}
+
+	url, err := presign(h.bucket, key, h.TTL)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, ErrInvalidKey):
+			status = http.StatusBadRequest
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	// URL 이 곧 만료되므로 redirect 응답을 캐시하지 않음
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, url, http.StatusFound)
+}
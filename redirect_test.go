@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRedirectHandlerDenied(t *testing.T) {
+	authorize := func(r *http.Request, key string) error {
+		switch r.Header.Get("Authorization") {
+		case "":
+			return ErrUnauthenticated
+		case "admin":
+			return nil
+		}
+		return errors.New("not owner")
+	}
+	handler := http.StripPrefix("/files", (&Storage{}).RedirectHandler("bucket", authorize))
+
+	cases := []struct {
+		method, path, auth string
+		status             int
+	}{
+		{http.MethodPost, "/files/a.txt", "admin", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/files/", "admin", http.StatusNotFound},
+		{http.MethodGet, "/files/a.txt", "", http.StatusUnauthorized},
+		{http.MethodGet, "/files/a.txt", "guest", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, nil)
+		req.Header.Set("Authorization", c.auth)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != c.status {
+			t.Errorf("%s %s %q: %d", c.method, c.path, c.auth, rec.Code)
+		}
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+	handler := http.StripPrefix("/files", store.RedirectHandler("bucket", func(*http.Request, string) error { return nil }))
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/files/docs/a.pdf", nil))
+		if rec.Code != http.StatusFound || rec.Header().Get("Cache-Control") != "no-store" {
+			t.Fatalf("%s: %d %v", method, rec.Code, rec.Header())
+		}
+
+		// method 와 key 가 서명된 presigned URL
+		location, err := url.Parse(rec.Header().Get("Location"))
+		if err != nil || location.Path != "/bucket/docs/a.pdf" || location.Query().Get("X-Amz-Expires") != "60" {
+			t.Fatalf("%s: location %v, %v", method, location, err)
+		}
+	}
+}
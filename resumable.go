@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/pro200/go-utils"
+)
+
+const (
+	defaultPartSize = 8 << 20
+	maxPartNumber   = 10000
+)
+
+var ErrOffsetMismatch = errors.New("chunk offset does not match upload state")
+
+// UploadState 는 진행 중인 multipart 업로드 상태
+// JSON 으로 저장해 두었다가 프로세스 재시작이나 며칠 뒤에도 이어서 업로드할 수 있다.
+type UploadState struct {
+	Bucket   string         `json:"bucket"`
+	Key      string         `json:"key"`
+	UploadID string         `json:"upload_id"`
+	PartSize int64          `json:"part_size"`
+	Size     int64          `json:"size,omitempty"` // 전체 크기, PresignUploadParts 가 기록
+	Parts    []UploadedPart `json:"parts"`          // Number 순 정렬
+
+	mu sync.Mutex
+}
+
+type UploadedPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+}
+
+// Offset 1번 part 부터 빠짐없이 올라간 다음 byte 위치
+func (u *UploadState) Offset() int64 {
+	_, offset := u.next()
+	return offset
+}
+
+// next 이어서 올릴 part 번호와 위치
+func (u *UploadState) next() (int32, int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var (
+		number int32 = 1
+		offset int64
+	)
+	for _, part := range u.Parts {
+		if part.Number != number {
+			break
+		}
+		number++
+		offset = part.Offset + part.Size
+	}
+	return number, offset
+}
+
+func (u *UploadState) addPart(part UploadedPart) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(u.Parts, part.Number, func(p UploadedPart, number int32) int {
+		return int(p.Number - number)
+	})
+	if found {
+		u.Parts[i] = part
+	} else {
+		u.Parts = slices.Insert(u.Parts, i, part)
+	}
+}
+
+// CreateMultipart 는 multipart 업로드를 시작하고 상태를 반환한다.
+// Content-Type 을 지정하지 않으면 key 확장자로 추론한다.
+func (s *Storage) CreateMultipart(bucket, key string, options ...UploadOption) (*UploadState, error) {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := s.options(bucket, options...)
+	if opt.ContentType == "" {
+		opt.ContentType = utils.ContentType(key)
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		ContentType:        aws.String(opt.ContentType),
+		CacheControl:       optionalString(opt.CacheControl),
+		ContentDisposition: optionalString(opt.ContentDisposition),
+		ContentEncoding:    optionalString(opt.ContentEncoding),
+		ContentLanguage:    optionalString(opt.ContentLanguage),
+		Metadata:           s.objectMetadata(opt),
+	}
+	if opt.StorageClass != "" {
+		input.StorageClass = types.StorageClass(opt.StorageClass)
+	}
+
+	output, err := s.client.CreateMultipartUpload(s.requestContext(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadState{
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: aws.ToString(output.UploadId),
+		PartSize: defaultPartSize,
+	}, nil
+}
+
+// UploadPart 는 number 번 part 를 업로드한다. part 의 위치는 (number-1) * state.PartSize.
+// 서로 다른 part 는 동시에 올릴 수 있고, 같은 번호를 다시 올리면 덮어쓴다.
+func (s *Storage) UploadPart(state *UploadState, number int32, body io.ReadSeeker, size int64) error {
+	if number < 1 || number > maxPartNumber {
+		return fmt.Errorf("invalid part number: %d", number)
+	}
+	return s.uploadPart(state, number, int64(number-1)*state.PartSize, body, size)
+}
+
+// UploadChunk 는 offset 위치의 data 를 다음 part 로 업로드한다.
+// 클라이언트가 보낸 조각을 그대로 전달하는 용도로, offset 이 state.Offset() 과 다르면 ErrOffsetMismatch.
+// 마지막을 제외한 조각은 5MB 이상이어야 한다 (S3 제한).
+func (s *Storage) UploadChunk(state *UploadState, offset int64, data []byte) error {
+	number, current := state.next()
+	if offset != current {
+		return fmt.Errorf("%w: got %d, want %d", ErrOffsetMismatch, offset, current)
+	}
+
+	return s.uploadPart(state, number, offset, bytes.NewReader(data), int64(len(data)))
+}
+
+// ContinueUpload 는 r 의 state.Offset() 부터 size 까지 남은 part 를 업로드하고 완료한다.
+// 중간에 실패해도 state 에는 성공한 part 까지 기록되어 있으므로 다시 호출하면 이어서 진행한다.
+func (s *Storage) ContinueUpload(state *UploadState, r io.ReaderAt, size int64) error {
+	if state.PartSize < minPartSize {
+		state.PartSize = defaultPartSize
+	}
+
+	for number, offset := state.next(); offset < size; number, offset = state.next() {
+		length := min(state.PartSize, size-offset)
+		if err := s.uploadPart(state, number, offset, io.NewSectionReader(r, offset, length), length); err != nil {
+			return err
+		}
+	}
+
+	return s.CompleteMultipart(state)
+}
+
+// CompleteMultipart 업로드한 part 들로 객체를 만든다.
+func (s *Storage) CompleteMultipart(state *UploadState) error {
+	state.mu.Lock()
+	parts := make([]types.CompletedPart, len(state.Parts))
+	var size int64
+	for i, part := range state.Parts {
+		parts[i] = types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(part.Number),
+		}
+		size += part.Size
+	}
+	state.mu.Unlock()
+
+	if len(parts) == 0 {
+		return errors.New("zero size file")
+	}
+
+	previous := s.sizeBefore(state.Bucket, state.Key)
+
+	_, err := s.client.CompleteMultipartUpload(s.requestContext(), &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(state.Bucket),
+		Key:             aws.String(state.Key),
+		UploadId:        aws.String(state.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.updateDirStats(state.Bucket, state.Key, previous, size)
+	return nil
+}
+
+// AbortMultipart 업로드를 취소하고 이미 올린 part 를 삭제한다.
+func (s *Storage) AbortMultipart(state *UploadState) error {
+	_, err := s.client.AbortMultipartUpload(s.requestContext(), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadID),
+	})
+	return err
+}
+
+func (s *Storage) uploadPart(state *UploadState, number int32, offset int64, body io.ReadSeeker, size int64) error {
+	if size == 0 {
+		return errors.New("zero size part")
+	}
+
+	output, err := s.client.UploadPart(s.requestContext(), &s3.UploadPartInput{
+		Bucket:        aws.String(state.Bucket),
+		Key:           aws.String(state.Key),
+		UploadId:      aws.String(state.UploadID),
+		PartNumber:    aws.Int32(number),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return err
+	}
+
+	state.addPart(UploadedPart{
+		Number: number,
+		ETag:   aws.ToString(output.ETag),
+		Offset: offset,
+		Size:   size,
+	})
+	return nil
+}
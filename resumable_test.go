@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUploadStateOffset(t *testing.T) {
+	state := &UploadState{PartSize: 10}
+	if state.Offset() != 0 {
+		t.Error("빈 상태의 offset 이 0 이 아님")
+	}
+
+	// 순서와 관계없이 올라간 part
+	state.addPart(UploadedPart{Number: 3, Offset: 20, Size: 10})
+	state.addPart(UploadedPart{Number: 1, Offset: 0, Size: 10})
+	if state.Offset() != 10 {
+		t.Error("2번 part 가 없으면 offset 은 10:", state.Offset())
+	}
+
+	state.addPart(UploadedPart{Number: 2, Offset: 10, Size: 10})
+	state.addPart(UploadedPart{Number: 2, Offset: 10, Size: 10, ETag: "again"})
+	if len(state.Parts) != 3 || state.Offset() != 30 {
+		t.Error("잘못된 상태:", state.Parts, state.Offset())
+	}
+
+	data, _ := json.Marshal(state)
+	restored := &UploadState{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if number, offset := restored.next(); number != 4 || offset != 30 {
+		t.Error("복원한 상태가 다름:", number, offset)
+	}
+}
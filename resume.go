@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// 받는 중인 데이터와 상태 파일 이름, targetPath 뒤에 붙임
+const (
+	partialSuffix = ".partial"
+	stateSuffix   = ".partial.json"
+)
+
+// partialState 는 받는 중인 파일이 어느 객체 버전의 일부인지 기록한다.
+type partialState struct {
+	ETag string `json:"etag"`
+	Size int64  `json:"size"`
+}
+
+// ResumeDownload 는 Download 와 같지만 targetPath.partial 에 받다가 끊긴 데이터가 있으면 이어서 받는다.
+// 이전에 받던 객체와 ETag, 크기가 같을 때만 이어 받고, 객체가 바뀌었으면 처음부터 받는다.
+// 실패해도 받은 데이터는 남겨 두므로 같은 호출을 다시 하면 된다. 완료되면 targetPath 로 이름을 바꾼다.
+// 검증 옵션(WithExpectedSHA256 등)은 완료 후 전체 파일에 적용하며, 실패하면 받은 데이터를 삭제한다.
+func (s *Storage) ResumeDownload(bucket, key, targetPath string, options ...DownloadOption) error {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return err
+	}
+
+	opt := newDownloadOptions(options)
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if opt.stored {
+		input.ChecksumMode = types.ChecksumModeEnabled
+	}
+	head, err := s.client.HeadObject(s.requestContext(), input)
+	if err != nil {
+		return err
+	}
+	if opt.stored {
+		opt.expectStored(head.ETag, head.ContentLength, head.ChecksumSHA256)
+	}
+
+	var (
+		partial   = targetPath + partialSuffix
+		statePath = targetPath + stateSuffix
+		current   = partialState{ETag: aws.ToString(head.ETag), Size: aws.ToInt64(head.ContentLength)}
+	)
+
+	offset, err := resumeOffset(partial, statePath, current)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(partial, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer file.Close()
+
+	// 이어 받을 수 없으면 처음부터
+	if err = file.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if offset < current.Size {
+		var w io.Writer = file
+		if opt.progress != nil {
+			w = &progressWriter{w: file, p: &progress{transferred: offset, total: current.Size, fn: opt.progress}}
+		}
+
+		output, err := s.client.GetObject(s.requestContext(), &s3.GetObjectInput{
+			Bucket:  aws.String(bucket),
+			Key:     aws.String(key),
+			Range:   aws.String(byteRange(offset, 0)),
+			IfMatch: head.ETag, // 받는 중 객체가 바뀌면 실패, 다음 호출에서 처음부터
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, output.Body)
+		output.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = opt.verifyFile(key, partial); err != nil {
+		os.Remove(partial)
+		os.Remove(statePath)
+		return err
+	}
+
+	if err = os.Rename(partial, targetPath); err != nil {
+		return err
+	}
+	return os.Remove(statePath)
+}
+
+// resumeOffset 은 partial 파일에서 이어 받을 위치. 상태가 없거나 다른 객체이면 상태를 새로 쓰고 0.
+func resumeOffset(partial, statePath string, current partialState) (int64, error) {
+	var previous partialState
+	data, err := os.ReadFile(statePath)
+	if err == nil && json.Unmarshal(data, &previous) == nil && previous == current {
+		if stat, err := os.Stat(partial); err == nil && stat.Size() <= current.Size {
+			return stat.Size(), nil
+		}
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	data, err = json.Marshal(current)
+	if err != nil {
+		return 0, err
+	}
+	return 0, os.WriteFile(statePath, data, 0o644)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeOffset(t *testing.T) {
+	dir := t.TempDir()
+	partial, statePath := filepath.Join(dir, "a.bin.partial"), filepath.Join(dir, "a.bin.partial.json")
+	current := partialState{ETag: `"abc"`, Size: 100}
+
+	// 상태 없음: 처음부터, 상태 기록
+	if offset, err := resumeOffset(partial, statePath, current); offset != 0 || err != nil {
+		t.Fatal(offset, err)
+	}
+
+	os.WriteFile(partial, make([]byte, 40), 0o644)
+	if offset, err := resumeOffset(partial, statePath, current); offset != 40 || err != nil {
+		t.Error(offset, err)
+	}
+
+	// 객체가 바뀜: 처음부터
+	changed := partialState{ETag: `"def"`, Size: 100}
+	if offset, err := resumeOffset(partial, statePath, changed); offset != 0 || err != nil {
+		t.Error(offset, err)
+	}
+
+	// 객체보다 큰 partial: 처음부터
+	os.WriteFile(partial, make([]byte, 120), 0o644)
+	if offset, err := resumeOffset(partial, statePath, changed); offset != 0 || err != nil {
+		t.Error(offset, err)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+// RetryRule 이 있을 때 기본 최대 시도 횟수 (SDK 기본 3)
+const tieredMaxAttempts = 8
+
+// RetryRule 은 일시적인 과부하라 더 오래 기다렸다가 재시도할 오류
+type RetryRule struct {
+	Codes      []string      // 오류 코드, 예: ServiceUnavailable
+	Messages   []string      // 오류 메시지에 포함된 문자열, 대소문자 무시
+	Backoff    time.Duration // 첫 재시도 대기, 시도마다 2배
+	MaxBackoff time.Duration
+}
+
+// B2 S3 gateway 는 저장 노드(tome)가 바쁘면 503 을 반환하며, 수 초 뒤에는 대개 성공한다.
+var providerRetryRules = map[SType][]RetryRule{
+	B2: {{
+		Codes:      []string{"ServiceUnavailable", "service_unavailable", "InternalError", "internal_error"},
+		Messages:   []string{"no tomes available", "service_unavailable"},
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+	}},
+}
+
+func (r RetryRule) match(code, message string) bool {
+	if slices.Contains(r.Codes, code) {
+		return true
+	}
+	message = strings.ToLower(message)
+	for _, m := range r.Messages {
+		if strings.Contains(message, strings.ToLower(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay 는 attempt(1부터) 번째 재시도 전 대기, 절반 이상 무작위
+func (r RetryRule) delay(attempt int) time.Duration {
+	d := r.Backoff << min(attempt-1, 16)
+	if r.MaxBackoff > 0 && (d > r.MaxBackoff || d <= 0) {
+		d = r.MaxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryRules provider 기본 규칙 뒤에 Config.RetryRules
+type retryRules []RetryRule
+
+func (rules retryRules) find(err error) (RetryRule, bool) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return RetryRule{}, false
+	}
+	for _, rule := range rules {
+		if rule.match(apiErr.ErrorCode(), apiErr.ErrorMessage()) {
+			return rule, true
+		}
+	}
+	return RetryRule{}, false
+}
+
+// newRetryer 는 SDK 기본 재시도에 rules 를 더한다. 규칙에 맞는 오류는 항상 재시도하고 규칙의 대기 시간을 쓴다.
+func newRetryer(rules retryRules, maxAttempts int) aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		if maxAttempts > 0 {
+			o.MaxAttempts = maxAttempts
+		}
+		if len(rules) == 0 {
+			return
+		}
+		if maxAttempts <= 0 {
+			o.MaxAttempts = tieredMaxAttempts
+		}
+
+		o.Retryables = append([]retry.IsErrorRetryable{retry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+			if _, ok := rules.find(err); ok {
+				return aws.TrueTernary
+			}
+			return aws.UnknownTernary
+		})}, o.Retryables...)
+		o.Backoff = tieredBackoff{rules: rules, fallback: retry.NewExponentialJitterBackoff(o.MaxBackoff)}
+		// 몰려오는 503 으로 재시도 토큰이 바닥나 작업이 실패하지 않도록
+		o.RateLimiter = ratelimit.None
+	})
+}
+
+type tieredBackoff struct {
+	rules    retryRules
+	fallback retry.BackoffDelayer
+}
+
+func (b tieredBackoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	if rule, ok := b.rules.find(err); ok {
+		return rule.delay(attempt), nil
+	}
+	return b.fallback.BackoffDelay(attempt, err)
+}
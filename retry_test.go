@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestRetryRules(t *testing.T) {
+	rules := retryRules(providerRetryRules[B2])
+
+	for _, err := range []error{
+		&smithy.GenericAPIError{Code: "ServiceUnavailable", Message: "no tomes available"},
+		fmt.Errorf("put: %w", &smithy.GenericAPIError{Code: "503", Message: "No Tomes Available"}),
+	} {
+		if _, ok := rules.find(err); !ok {
+			t.Error("재시도 대상이 아님:", err)
+		}
+	}
+
+	for _, err := range []error{
+		&smithy.GenericAPIError{Code: "NoSuchKey", Message: "not found"},
+		errors.New("service_unavailable"), // API 오류가 아님
+	} {
+		if _, ok := rules.find(err); ok {
+			t.Error("재시도 대상이 됨:", err)
+		}
+	}
+}
+
+func TestRetryRuleDelay(t *testing.T) {
+	rule := RetryRule{Backoff: time.Second, MaxBackoff: 30 * time.Second}
+	for attempt, max := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 10: 30 * time.Second, 100: 30 * time.Second} {
+		if d := rule.delay(attempt); d < max/2 || d > max {
+			t.Errorf("attempt %d: %v", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// RetryingReader 는 원본 읽기가 일시적으로 실패하면 같은 위치로 다시 seek 하여 재시도한다.
+// NFS, FUSE 마운트처럼 가끔 읽기 오류가 나는 원본 때문에 수 GB 업로드 전체가 실패하지 않도록 한다.
+type RetryingReader struct {
+	r        io.ReadSeeker
+	offset   int64
+	Attempts int           // 기본 3
+	Backoff  time.Duration // 재시도 간격, 시도마다 두 배 (기본 100ms)
+}
+
+func NewRetryingReader(r io.ReadSeeker) *RetryingReader {
+	return &RetryingReader{r: r, Attempts: 3, Backoff: 100 * time.Millisecond}
+}
+
+func (rr *RetryingReader) Read(p []byte) (int, error) {
+	var (
+		n       int
+		err     error
+		backoff = rr.Backoff
+	)
+
+	for attempt := 0; ; attempt++ {
+		n, err = rr.r.Read(p)
+		rr.offset += int64(n)
+		if err == nil || errors.Is(err, io.EOF) || attempt+1 >= rr.Attempts {
+			return n, err
+		}
+
+		// 실패한 위치로 되돌린 뒤 재시도
+		if _, seekErr := rr.r.Seek(rr.offset, io.SeekStart); seekErr != nil {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (rr *RetryingReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := rr.r.Seek(offset, whence)
+	if err == nil {
+		rr.offset = pos
+	}
+	return pos, err
+}
+
+// ReadAt 원본이 io.ReaderAt 이면 병렬 part 업로드에 사용된다.
+func (rr *RetryingReader) ReadAt(p []byte, off int64) (int, error) {
+	ra, ok := rr.r.(io.ReaderAt)
+	if !ok {
+		return 0, errors.New("source does not support ReadAt")
+	}
+
+	var (
+		read    int
+		backoff = rr.Backoff
+	)
+
+	for attempt := 0; ; attempt++ {
+		n, err := ra.ReadAt(p[read:], off+int64(read))
+		read += n
+		if err == nil || errors.Is(err, io.EOF) || attempt+1 >= rr.Attempts {
+			return read, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,40 @@
+package storage_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+// flakyReader 는 지정한 위치에서 한 번 읽기 오류를 낸다.
+type flakyReader struct {
+	*bytes.Reader
+	failAt int64
+	failed bool
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	pos := f.Size() - int64(f.Len())
+	if !f.failed && pos >= f.failAt {
+		f.failed = true
+		return 0, errors.New("input/output error")
+	}
+	return f.Reader.Read(p[:min(len(p), 3)])
+}
+
+func TestRetryingReader(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	reader := storage.NewRetryingReader(&flakyReader{Reader: bytes.NewReader(data), failAt: 6})
+	reader.Backoff = 0
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+}
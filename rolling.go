@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// RollingWriter 는 쓰기를 gzip 으로 모아 두었다가 rollInterval 또는 maxSize 마다
+// <prefixTemplate>part-0001<Ext> 형태의 객체로 업로드한다.
+// prefixTemplate 의 {yyyy}, {MM}, {dd}, {HH}, {mm} 은 첫 쓰기 시각(UTC)으로 바꾼다. 예: "logs/{yyyy}/{MM}/{dd}/{HH}/"
+// 그 밖의 글자는 그대로 쓰므로 prefix 에 숫자가 들어가도 시각으로 바뀌지 않는다.
+type RollingWriter struct {
+	Ext string // default: .gz
+
+	storage        *Storage
+	bucket         string
+	prefixTemplate string
+	maxSize        int64
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	gz     *gzip.Writer
+	opened time.Time
+	prefix string
+	part   int
+	err    error
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func (s *Storage) RollingWriter(bucket, prefixTemplate string, rollInterval time.Duration, maxSize int64) *RollingWriter {
+	w := &RollingWriter{
+		Ext:            ".gz",
+		storage:        s,
+		bucket:         bucket,
+		prefixTemplate: prefixTemplate,
+		maxSize:        maxSize,
+		done:           make(chan struct{}),
+	}
+
+	if rollInterval > 0 {
+		w.wg.Add(1)
+		go w.loop(rollInterval)
+	}
+
+	return w
+}
+
+func (w *RollingWriter) loop(interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.gz != nil && time.Since(w.opened) >= interval {
+				w.err = w.flush()
+			}
+			w.mu.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *RollingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if w.gz == nil {
+		w.opened = time.Now().UTC()
+		w.gz = gzip.NewWriter(&w.buf)
+	}
+
+	n, err := w.gz.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	if w.maxSize > 0 && int64(w.buf.Len()) >= w.maxSize {
+		w.err = w.flush()
+	}
+
+	return n, w.err
+}
+
+// Flush 는 모아 둔 데이터를 즉시 업로드한다.
+func (w *RollingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.gz == nil {
+		return w.err
+	}
+	w.err = w.flush()
+	return w.err
+}
+
+// Close 는 남은 데이터를 업로드하고 백그라운드 flush 를 멈춘다. 여러 번 호출해도 된다.
+func (w *RollingWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+	})
+	return w.Flush()
+}
+
+// expandPrefix 는 prefixTemplate 의 시각 placeholder 를 t 로 바꾼다.
+func expandPrefix(template string, t time.Time) string {
+	return strings.NewReplacer(
+		"{yyyy}", t.Format("2006"),
+		"{MM}", t.Format("01"),
+		"{dd}", t.Format("02"),
+		"{HH}", t.Format("15"),
+		"{mm}", t.Format("04"),
+	).Replace(template)
+}
+
+func (w *RollingWriter) flush() error {
+	if err := w.gz.Close(); err != nil {
+		return err
+	}
+
+	prefix := expandPrefix(w.prefixTemplate, w.opened)
+	if prefix != w.prefix {
+		w.prefix = prefix
+		w.part = 0
+	}
+
+	data := w.buf.Bytes()
+	for {
+		w.part++
+		key := fmt.Sprintf("%spart-%04d%s", prefix, w.part, w.Ext)
+		if err := w.storage.config.Policy.Allow(OpPut, key); err != nil {
+			return err
+		}
+
+		// 재시작한 프로세스가 기존 part 를 덮어쓰지 않도록 조건부 PUT
+		_, err := w.storage.client.PutObject(w.storage.requestContext(), &s3.PutObjectInput{
+			Bucket:        aws.String(w.bucket),
+			Key:           aws.String(key),
+			Body:          bytes.NewReader(data),
+			ContentLength: aws.Int64(int64(len(data))),
+			ContentType:   aws.String("application/gzip"),
+			IfNoneMatch:   aws.String("*"),
+		})
+		if isPreconditionFailed(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	w.buf.Reset()
+	w.gz = nil
+	return nil
+}
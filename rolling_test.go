@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestExpandPrefix(t *testing.T) {
+	at := time.Date(2024, 5, 12, 13, 7, 0, 0, time.UTC)
+	if got := expandPrefix("logs/{yyyy}/{MM}/{dd}/{HH}/{mm}/", at); got != "logs/2024/05/12/13/07/" {
+		t.Errorf("got %q", got)
+	}
+
+	// placeholder 밖의 숫자는 그대로
+	if got := expandPrefix("app-1/2006/{yyyy}/", at); got != "app-1/2006/2024/" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRollingWriter(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	prefix := expandPrefix("logs/{yyyy}/{MM}/", time.Now().UTC())
+
+	// 재시작 전 프로세스가 올린 part
+	fake.put("bucket", prefix+"part-0001.gz", []byte("old"))
+
+	w := store.RollingWriter("bucket", "logs/{yyyy}/{MM}/", 0, 0)
+	w.Write([]byte("first"))
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("second"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	if string(fake.get("bucket", prefix+"part-0001.gz")) != "old" {
+		t.Fatal("existing part overwritten")
+	}
+	for key, want := range map[string]string{prefix + "part-0002.gz": "first", prefix + "part-0003.gz": "second"} {
+		r, err := gzip.NewReader(bytes.NewReader(fake.get("bucket", key)))
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if data, _ := io.ReadAll(r); string(data) != want {
+			t.Errorf("%s: %q", key, data)
+		}
+	}
+}
+
+func TestRollingWriterMaxSize(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	w := store.RollingWriter("bucket", "logs/", time.Hour, 1)
+	w.Ext = ".json.gz"
+
+	w.Write([]byte("a"))
+	w.Write([]byte("b"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if keys := fake.keys("bucket"); len(keys) != 2 || keys[1] != "logs/part-0002.json.gz" {
+		t.Fatalf("uploaded %v", keys)
+	}
+}
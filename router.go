@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pro200/go-utils"
+)
+
+var ErrNoRoute = errors.New("no route matched")
+
+// Route 는 조건에 맞는 객체를 저장할 위치
+// 조건 필드가 비어 있으면(0) 해당 조건은 검사하지 않는다.
+type Route struct {
+	Name         string
+	Storage      *Storage
+	Bucket       string
+	StorageClass string // 비어 있으면 bucket 기본값
+
+	KeyPattern   string   // path.Match 패턴, 예: "thumbs/*"
+	ContentTypes []string // prefix 비교, 예: "video/"
+	MinSize      int64
+	MaxSize      int64
+}
+
+func (r *Route) match(key, contentType string, size int64) bool {
+	if r.KeyPattern != "" {
+		if ok, _ := path.Match(r.KeyPattern, key); !ok {
+			return false
+		}
+	}
+
+	if len(r.ContentTypes) > 0 {
+		matched := false
+		for _, prefix := range r.ContentTypes {
+			if strings.HasPrefix(contentType, prefix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	// size 를 모르면(-1) 크기 조건이 있는 route 는 건너뜀
+	if (r.MinSize > 0 || r.MaxSize > 0) && size < 0 {
+		return false
+	}
+	if r.MinSize > 0 && size < r.MinSize {
+		return false
+	}
+	if r.MaxSize > 0 && size > r.MaxSize {
+		return false
+	}
+
+	return true
+}
+
+// Router 는 key, Content-Type, 크기에 따라 저장 위치를 고른다.
+// Routes 는 순서대로 검사하며 처음 일치한 route 를 사용한다.
+type Router struct {
+	Routes []Route
+	Index  PlacementIndex // nil 이면 읽을 때 모든 route 를 순서대로 조회
+}
+
+func NewRouter(routes ...Route) *Router {
+	return &Router{Routes: routes}
+}
+
+// Route 일치하는 route 반환, size 를 모르면 -1
+func (r *Router) Route(key, contentType string, size int64) (*Route, error) {
+	for i := range r.Routes {
+		if r.Routes[i].match(key, contentType, size) {
+			return &r.Routes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoRoute, key)
+}
+
+// Put 은 origin 의 크기와 Content-Type 으로 route 를 골라 업로드하고 선택된 route 를 반환한다.
+// 원격 origin 은 첫 번째 route 의 Storage 설정(Transport, WithContext 등)으로 HEAD 요청을 보낸다.
+func (r *Router) Put(key, origin string, options ...UploadOption) (*Route, error) {
+	var opt Options
+	for _, option := range options {
+		option.applyUpload(&opt)
+	}
+
+	var probe *Storage
+	for i := range r.Routes {
+		if r.Routes[i].Storage != nil {
+			probe = r.Routes[i].Storage
+			break
+		}
+	}
+	if probe == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRoute, key)
+	}
+
+	contentType, size, err := probe.probeOrigin(origin, opt.Headers)
+	if err != nil {
+		return nil, err
+	}
+	if opt.ContentType != "" {
+		contentType = opt.ContentType
+	}
+
+	route, err := r.Route(key, contentType, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.StorageClass == "" {
+		opt.StorageClass = route.StorageClass
+	}
+
+	if err = route.Storage.Upload(route.Bucket, key, origin, opt); err != nil {
+		return nil, err
+	}
+
+	if r.Index != nil {
+		if err = r.Index.Set(key, route.Name); err != nil {
+			return route, err
+		}
+	}
+	return route, nil
+}
+
+// Locate 는 key 가 저장된 route 를 찾는다.
+// index 에 없으면 route 들을 순서대로 HEAD 요청하여 확인하고 index 를 갱신한다.
+func (r *Router) Locate(key string) (*Route, error) {
+	if r.Index != nil {
+		name, ok, err := r.Index.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			for i := range r.Routes {
+				if r.Routes[i].Name == name {
+					return &r.Routes[i], nil
+				}
+			}
+		}
+	}
+
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		_, err := route.Storage.Info(route.Bucket, key)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Index != nil {
+			if err = r.Index.Set(key, route.Name); err != nil {
+				return nil, err
+			}
+		}
+		return route, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotPlaced, key)
+}
+
+// Download 는 key 가 저장된 위치에서 내려받는다.
+func (r *Router) Download(key, targetPath string) error {
+	route, err := r.Locate(key)
+	if err != nil {
+		return err
+	}
+	return route.Storage.Download(route.Bucket, key, targetPath)
+}
+
+// probeOrigin origin 의 Content-Type 과 크기, 원격 origin 은 HEAD 요청으로 확인
+func (s *Storage) probeOrigin(origin string, headers map[string]string) (string, int64, error) {
+	if !strings.HasPrefix(origin, "https://") {
+		stat, err := os.Stat(origin)
+		if err != nil {
+			return "", 0, err
+		}
+		return utils.ContentType(origin), stat.Size(), nil
+	}
+
+	req, _ := http.NewRequestWithContext(s.requestContext(), http.MethodHead, origin, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("origin probe failed: %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = utils.ContentType(origin)
+	}
+
+	// ContentLength 를 모르면 -1
+	return contentType, resp.ContentLength, nil
+}
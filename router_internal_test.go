@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRouterPutRemoteOrigin(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.put("origin", "a.mp4", []byte("video"))
+
+	router := NewRouter(
+		Route{Name: "videos", Storage: store, Bucket: "videos", ContentTypes: []string{"video/"}, MaxSize: 1 << 20},
+	)
+
+	// 원격 origin 도 Storage 의 Transport 로 확인
+	route, err := router.Put("a.mp4", "https://origin.example/origin/a.mp4")
+	if err != nil || route.Name != "videos" {
+		t.Fatalf("route %v, %v", route, err)
+	}
+	if string(fake.get("videos", "a.mp4")) != "video" {
+		t.Fatalf("uploaded %v", fake.keys("videos"))
+	}
+
+	if _, err := router.Put("b.mp4", "https://origin.example/origin/missing.mp4"); err == nil {
+		t.Error("expected probe error for missing origin")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	router.Routes[0].Storage = store.WithContext(ctx)
+	if _, err := router.Put("a.mp4", "https://origin.example/origin/a.mp4"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
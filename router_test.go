@@ -0,0 +1,61 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestRouter(t *testing.T) {
+	router := storage.NewRouter(
+		storage.Route{Name: "thumbs", Bucket: "r2-thumbs", KeyPattern: "thumbs/*"},
+		storage.Route{Name: "videos", Bucket: "b2-videos", ContentTypes: []string{"video/"}, StorageClass: "STANDARD_IA"},
+		storage.Route{Name: "large", Bucket: "b2-large", MinSize: 100 << 20},
+		storage.Route{Name: "small", Bucket: "r2-small", MaxSize: 100 << 20},
+	)
+
+	cases := []struct {
+		key         string
+		contentType string
+		size        int64
+		want        string
+	}{
+		{"thumbs/a.jpg", "image/jpeg", 1 << 30, "thumbs"},
+		{"movies/a.mp4", "video/mp4", 10, "videos"},
+		{"backup/a.tar", "application/x-tar", 1 << 30, "large"},
+		{"docs/a.pdf", "application/pdf", 1 << 10, "small"},
+	}
+
+	for _, c := range cases {
+		route, err := router.Route(c.key, c.contentType, c.size)
+		if err != nil {
+			t.Fatal(c.key, err)
+		}
+		if route.Name != c.want {
+			t.Errorf("%s: got %s, want %s", c.key, route.Name, c.want)
+		}
+	}
+
+	// 크기를 모르면 크기 조건 route 는 선택되지 않음
+	if _, err := router.Route("docs/a.pdf", "application/pdf", -1); !errors.Is(err, storage.ErrNoRoute) {
+		t.Error("ErrNoRoute 가 아님:", err)
+	}
+}
+
+func TestRouterLocate(t *testing.T) {
+	router := storage.NewRouter(
+		storage.Route{Name: "hot", Bucket: "r2-hot"},
+		storage.Route{Name: "cold", Bucket: "b2-cold"},
+	)
+	router.Index = storage.NewMemoryIndex()
+	router.Index.Set("archive/2020.tar", "cold")
+
+	route, err := router.Locate("archive/2020.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Name != "cold" || route.Bucket != "b2-cold" {
+		t.Error("잘못된 route:", route.Name)
+	}
+}
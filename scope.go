@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOutOfScope    = errors.New("presign request out of scope")
+	ErrTTLOutOfRange = errors.New("presign ttl out of range")
+)
+
+const (
+	maxPresignTTL     = 7 * 24 * time.Hour // SigV4 presigned URL 의 최대 유효 기간
+	defaultPresignTTL = 15 * time.Minute   // ttl 0 일 때 SDK 기본값
+)
+
+// TTLPolicy 는 presigned URL 유효 기간 범위. 0 이면 제한 없음, Max 는 항상 7일 이하로 적용한다.
+type TTLPolicy struct {
+	Min   time.Duration // 예: time.Minute
+	Max   time.Duration // 예: 24 * time.Hour
+	Clamp bool          // true 이면 범위 밖 ttl 을 Min/Max 로 맞추고, false 이면 *TTLError
+}
+
+// TTLError 는 요청한 ttl 이 TTLPolicy 범위 밖일 때 반환된다. errors.Is(err, ErrTTLOutOfRange) 도 true.
+type TTLError struct {
+	TTL      time.Duration
+	Min, Max time.Duration
+}
+
+func (e *TTLError) Error() string {
+	return fmt.Sprintf("presign ttl %s out of range [%s, %s]", e.TTL, e.Min, e.Max)
+}
+
+func (e *TTLError) Is(target error) bool {
+	return target == ErrTTLOutOfRange
+}
+
+// apply 는 범위에 맞춘 ttl. 0 은 SDK 기본값(15분)으로 보고, 음수는 Clamp 여부와 관계없이 오류.
+func (p TTLPolicy) apply(ttl time.Duration) (time.Duration, error) {
+	if ttl == 0 {
+		ttl = defaultPresignTTL
+	}
+
+	maxTTL := maxPresignTTL
+	if p.Max > 0 {
+		maxTTL = min(p.Max, maxPresignTTL)
+	}
+
+	switch {
+	case ttl > 0 && ttl >= p.Min && ttl <= maxTTL:
+		return ttl, nil
+	case ttl > 0 && p.Clamp:
+		return min(max(ttl, p.Min), maxTTL), nil
+	default:
+		return 0, &TTLError{TTL: ttl, Min: p.Min, Max: maxTTL}
+	}
+}
+
+// Scope 는 presigned URL 발급 범위를 제한한다.
+// SigV4 presigned URL 은 서명 자체에 method 와 key 가 포함되므로
+// 발급 시점에 범위를 검사하면 유출된 URL 도 그 범위를 벗어날 수 없다.
+type Scope struct {
+	Bucket  string // 비어 있으면 모든 버킷 허용
+	Prefix  string
+	Methods []string      // 비어 있으면 GET, PUT 모두 허용
+	MaxTTL  time.Duration // 0 이면 제한 없음, Config.PresignTTL 을 적용한 ttl 과 비교한다
+}
+
+type ScopedPresigner struct {
+	storage *Storage
+	scope   Scope
+}
+
+// Scoped 는 scope 안에서만 URL 을 발급하는 presigner 를 반환한다.
+func (s *Storage) Scoped(scope Scope) *ScopedPresigner {
+	return &ScopedPresigner{storage: s, scope: scope}
+}
+
+func (p *ScopedPresigner) PresignGet(bucket, key string, ttl time.Duration, options ...PresignOption) (string, error) {
+	ttl, err := p.check(http.MethodGet, bucket, key, ttl)
+	if err != nil {
+		return "", err
+	}
+	return p.storage.PresignGet(bucket, key, ttl, options...)
+}
+
+func (p *ScopedPresigner) PresignPut(bucket, key string, ttl time.Duration, options ...PresignOption) (string, error) {
+	ttl, err := p.check(http.MethodPut, bucket, key, ttl)
+	if err != nil {
+		return "", err
+	}
+	return p.storage.PresignPut(bucket, key, ttl, options...)
+}
+
+// check 는 범위를 검사하고 실제로 서명할 ttl 을 반환한다.
+// ttl 0(SDK 기본 15분)이나 Clamp 로 늘어난 ttl 이 MaxTTL 을 우회하지 않도록 TTLPolicy 를 먼저 적용한다.
+func (p *ScopedPresigner) check(method, bucket, key string, ttl time.Duration) (time.Duration, error) {
+	if len(p.scope.Methods) > 0 && !slices.Contains(p.scope.Methods, method) {
+		return 0, fmt.Errorf("%w: method %s", ErrOutOfScope, method)
+	}
+
+	if p.scope.Bucket != "" && bucket != p.scope.Bucket {
+		return 0, fmt.Errorf("%w: bucket %q", ErrOutOfScope, bucket)
+	}
+
+	if !strings.HasPrefix(key, p.scope.Prefix) {
+		return 0, fmt.Errorf("%w: key %q", ErrOutOfScope, key)
+	}
+
+	ttl, err := p.storage.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return 0, err
+	}
+	if p.scope.MaxTTL > 0 && ttl > p.scope.MaxTTL {
+		return 0, fmt.Errorf("%w: ttl %s", ErrOutOfScope, ttl)
+	}
+
+	return ttl, nil
+}
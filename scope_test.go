@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTTLPolicy(t *testing.T) {
+	policy := TTLPolicy{Min: time.Minute, Max: 24 * time.Hour}
+	if ttl, err := policy.apply(time.Hour); ttl != time.Hour || err != nil {
+		t.Error(ttl, err)
+	}
+
+	var ttlErr *TTLError
+	if _, err := policy.apply(48 * time.Hour); !errors.As(err, &ttlErr) || !errors.Is(err, ErrTTLOutOfRange) {
+		t.Error("범위 밖 ttl 이 허용됨:", err)
+	}
+
+	policy.Clamp = true
+	for requested, want := range map[time.Duration]time.Duration{time.Second: time.Minute, 48 * time.Hour: 24 * time.Hour} {
+		if ttl, err := policy.apply(requested); ttl != want || err != nil {
+			t.Error(requested, ttl, err)
+		}
+	}
+	if ttl, err := policy.apply(0); ttl != defaultPresignTTL || err != nil {
+		t.Error(ttl, err)
+	}
+	if _, err := policy.apply(-time.Second); err == nil {
+		t.Error("음수 ttl 이 허용됨")
+	}
+
+	// 정책이 없어도 SigV4 최대 7일
+	if ttl, err := (TTLPolicy{Clamp: true}).apply(30 * 24 * time.Hour); ttl != maxPresignTTL || err != nil {
+		t.Error(ttl, err)
+	}
+}
+
+func TestScopedPresigner(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{PresignTTL: TTLPolicy{Max: time.Hour, Clamp: true}})
+	uploads := store.Scoped(Scope{
+		Bucket:  "bucket",
+		Prefix:  "users/42/",
+		Methods: []string{http.MethodPut},
+		MaxTTL:  30 * time.Minute,
+	})
+
+	if _, err := uploads.PresignPut("bucket", "users/42/a.png", 10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, presign := range map[string]func() (string, error){
+		"bucket": func() (string, error) { return uploads.PresignPut("other", "users/42/a.png", time.Minute) },
+		"prefix": func() (string, error) { return uploads.PresignPut("bucket", "users/43/a.png", time.Minute) },
+		"method": func() (string, error) { return uploads.PresignGet("bucket", "users/42/a.png", time.Minute) },
+		"ttl":    func() (string, error) { return uploads.PresignPut("bucket", "users/42/a.png", 2*time.Hour) },
+	} {
+		if _, err := presign(); !errors.Is(err, ErrOutOfScope) {
+			t.Errorf("%s: expected ErrOutOfScope, got %v", name, err)
+		}
+	}
+
+	// ttl 0 은 SDK 기본값 15분으로 보고 MaxTTL 과 비교
+	short := store.Scoped(Scope{MaxTTL: 5 * time.Minute})
+	if _, err := short.PresignGet("bucket", "a", 0); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("ttl 0: expected ErrOutOfScope, got %v", err)
+	}
+	raw, err := short.PresignGet("bucket", "a", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := url.Parse(raw); u.Query().Get("X-Amz-Expires") != "300" {
+		t.Errorf("signed %s", raw)
+	}
+}
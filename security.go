@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// GetBucketPolicy 버킷 정책(JSON) 조회, 정책이 없으면 ""
+func (s *Storage) GetBucketPolicy(bucket string) (string, error) {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return "", err
+	}
+
+	output, err := s.client.GetBucketPolicy(s.requestContext(), &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if errorCode(err) == "NoSuchBucketPolicy" {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.Policy), nil
+}
+
+// PutBucketPolicy 버킷 정책(JSON)을 교체한다. 빈 문자열이면 정책을 삭제.
+func (s *Storage) PutBucketPolicy(bucket, policy string) error {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return err
+	}
+
+	if policy == "" {
+		_, err := s.client.DeleteBucketPolicy(s.requestContext(), &s3.DeleteBucketPolicyInput{
+			Bucket: aws.String(bucket),
+		})
+		return err
+	}
+
+	_, err := s.client.PutBucketPolicy(s.requestContext(), &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	})
+	return err
+}
+
+// PublicAccessBlock 은 버킷의 공개 접근 차단 설정
+type PublicAccessBlock struct {
+	BlockPublicAcls       bool
+	IgnorePublicAcls      bool
+	BlockPublicPolicy     bool
+	RestrictPublicBuckets bool
+}
+
+// GetPublicAccessBlock 설정이 없으면 모두 false
+func (s *Storage) GetPublicAccessBlock(bucket string) (*PublicAccessBlock, error) {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return nil, err
+	}
+
+	output, err := s.client.GetPublicAccessBlock(s.requestContext(), &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+	})
+	if errorCode(err) == "NoSuchPublicAccessBlockConfiguration" {
+		return &PublicAccessBlock{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block := &PublicAccessBlock{}
+	if c := output.PublicAccessBlockConfiguration; c != nil {
+		block.BlockPublicAcls = aws.ToBool(c.BlockPublicAcls)
+		block.IgnorePublicAcls = aws.ToBool(c.IgnorePublicAcls)
+		block.BlockPublicPolicy = aws.ToBool(c.BlockPublicPolicy)
+		block.RestrictPublicBuckets = aws.ToBool(c.RestrictPublicBuckets)
+	}
+	return block, nil
+}
+
+func (s *Storage) PutPublicAccessBlock(bucket string, block PublicAccessBlock) error {
+	if err := s.config.Policy.Allow(OpBucket, ""); err != nil {
+		return err
+	}
+
+	_, err := s.client.PutPublicAccessBlock(s.requestContext(), &s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+		PublicAccessBlockConfiguration: &types.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(block.BlockPublicAcls),
+			IgnorePublicAcls:      aws.Bool(block.IgnorePublicAcls),
+			BlockPublicPolicy:     aws.Bool(block.BlockPublicPolicy),
+			RestrictPublicBuckets: aws.Bool(block.RestrictPublicBuckets),
+		},
+	})
+	return err
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucketPolicy(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+
+	if policy, err := store.GetBucketPolicy("bucket"); err != nil || policy != "" {
+		t.Fatalf("no policy: %q, %v", policy, err)
+	}
+
+	const policy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"}]}`
+	if err := store.PutBucketPolicy("bucket", policy); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetBucketPolicy("bucket"); err != nil || got != policy {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	// 빈 문자열이면 삭제
+	if err := store.PutBucketPolicy("bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetBucketPolicy("bucket"); err != nil || got != "" {
+		t.Fatalf("deleted: %q, %v", got, err)
+	}
+}
+
+func TestPublicAccessBlock(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{})
+
+	if block, err := store.GetPublicAccessBlock("bucket"); err != nil || *block != (PublicAccessBlock{}) {
+		t.Fatalf("no block: %+v, %v", block, err)
+	}
+
+	want := PublicAccessBlock{BlockPublicAcls: true, BlockPublicPolicy: true}
+	if err := store.PutPublicAccessBlock("bucket", want); err != nil {
+		t.Fatal(err)
+	}
+	if block, err := store.GetPublicAccessBlock("bucket"); err != nil || *block != want {
+		t.Fatalf("got %+v, %v", block, err)
+	}
+}
+
+func TestSecurityPolicyDenied(t *testing.T) {
+	store, _ := newFakeStorage(t, Config{Policy: &Policy{Rules: []Rule{{Operations: []Operation{OpGet}}}}})
+
+	if _, err := store.GetBucketPolicy("bucket"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("GetBucketPolicy %v", err)
+	}
+	if err := store.PutBucketPolicy("bucket", ""); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("PutBucketPolicy %v", err)
+	}
+	if _, err := store.GetPublicAccessBlock("bucket"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("GetPublicAccessBlock %v", err)
+	}
+	if err := store.PutPublicAccessBlock("bucket", PublicAccessBlock{}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("PutPublicAccessBlock %v", err)
+	}
+}
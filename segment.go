@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pro200/go-utils"
+)
+
+// HLS / DASH 파일 Content-Type, 그 밖의 확장자는 utils.ContentType
+var segmentContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".mpd":  "application/dash+xml",
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".vtt":  "text/vtt",
+}
+
+// SegmentSink 는 패키저(HLS/DASH)가 만드는 segment 파일을 만들어지는 대로 업로드한다.
+// playlist 는 그 전에 추가한 segment 가 모두 올라간 뒤, 추가한 순서대로 업로드되므로
+// 플레이어가 아직 없는 segment 를 참조하는 playlist 를 받지 않는다.
+//
+//	sink := store.SegmentSink("bucket", "videos/42/")
+//	sink.AddSegment("720p/seg-001.ts", "/tmp/out/720p/seg-001.ts")
+//	sink.AddPlaylist("720p/index.m3u8", "/tmp/out/720p/index.m3u8")
+//	err := sink.Close()
+type SegmentSink struct {
+	Concurrency          int    // 동시 segment 업로드 수, default: 4
+	SegmentCacheControl  string // default: public, max-age=31536000, immutable
+	PlaylistCacheControl string // default: no-cache (라이브 playlist 는 계속 바뀜)
+
+	storage *Storage
+	bucket  string
+	prefix  string
+
+	once     sync.Once
+	slots    chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	pending  []chan struct{} // 마지막 playlist 이후 추가한 segment
+	playlist chan struct{}   // 마지막 playlist 업로드 완료
+	err      error
+}
+
+func (s *Storage) SegmentSink(bucket, prefix string) *SegmentSink {
+	return &SegmentSink{
+		Concurrency:          4,
+		SegmentCacheControl:  "public, max-age=31536000, immutable",
+		PlaylistCacheControl: "no-cache",
+		storage:              s,
+		bucket:               bucket,
+		prefix:               prefix,
+	}
+}
+
+// AddSegment 는 local 파일을 prefix + name 으로 업로드한다.
+// 동시 업로드가 Concurrency 만큼 진행 중이면 자리가 날 때까지 기다린다.
+func (k *SegmentSink) AddSegment(name, local string) {
+	k.once.Do(func() {
+		k.slots = make(chan struct{}, max(k.Concurrency, 1))
+	})
+	k.slots <- struct{}{}
+
+	done := make(chan struct{})
+	k.mu.Lock()
+	k.pending = append(k.pending, done)
+	k.mu.Unlock()
+
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		defer close(done)
+		defer func() { <-k.slots }()
+
+		k.fail(name, k.storage.Upload(k.bucket, k.prefix+name, local, k.options(name, k.SegmentCacheControl)))
+	}()
+}
+
+// AddPlaylist 는 지금까지 추가한 segment 와 이전 playlist 가 모두 업로드된 뒤 playlist 를 업로드한다.
+// 기다리는 동안 호출한 쪽은 막히지 않는다. 앞선 업로드가 실패했으면 playlist 는 올리지 않는다.
+// 패키저는 같은 playlist 파일을 계속 덮어쓰므로 호출 시점의 내용을 읽어 두었다가 업로드한다.
+func (k *SegmentSink) AddPlaylist(name, local string) {
+	data, err := os.ReadFile(local)
+	if err != nil {
+		k.fail(name, err)
+		return
+	}
+
+	k.mu.Lock()
+	segments := k.pending
+	previous := k.playlist
+	done := make(chan struct{})
+	k.pending, k.playlist = nil, done
+	k.mu.Unlock()
+
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		defer close(done)
+
+		for _, segment := range segments {
+			<-segment
+		}
+		if previous != nil {
+			<-previous
+		}
+		if k.Err() != nil {
+			return
+		}
+
+		k.fail(name, k.storage.UploadReaderAt(k.bucket, k.prefix+name, bytes.NewReader(data), int64(len(data)), k.options(name, k.PlaylistCacheControl)))
+	}()
+}
+
+// Close 는 모든 업로드가 끝날 때까지 기다리고 첫 번째 오류를 반환한다.
+func (k *SegmentSink) Close() error {
+	k.wg.Wait()
+	return k.Err()
+}
+
+// Err 는 지금까지 발생한 첫 번째 업로드 오류
+func (k *SegmentSink) Err() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.err
+}
+
+func (k *SegmentSink) options(name, cacheControl string) Options {
+	return Options{
+		ContentType:  segmentContentType(name),
+		CacheControl: cacheControl,
+	}
+}
+
+// fail 은 첫 번째 오류를 기록한다.
+func (k *SegmentSink) fail(name string, err error) {
+	if err == nil {
+		return
+	}
+
+	k.mu.Lock()
+	if k.err == nil {
+		k.err = fmt.Errorf("%s: %w", name, err)
+	}
+	k.mu.Unlock()
+}
+
+func segmentContentType(name string) string {
+	if contentType, ok := segmentContentTypes[strings.ToLower(path.Ext(name))]; ok {
+		return contentType
+	}
+	return utils.ContentType(name)
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSegmentContentType(t *testing.T) {
+	tests := map[string]string{
+		"720p/index.m3u8":  "application/vnd.apple.mpegurl",
+		"720p/seg-001.TS":  "video/mp2t",
+		"dash/chunk-1.m4s": "video/iso.segment",
+		"manifest.mpd":     "application/dash+xml",
+		"thumb.jpg":        "image/jpeg",
+	}
+
+	for name, want := range tests {
+		if got := segmentContentType(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSegmentSinkPlaylistSnapshot(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	// segment 업로드가 끝나기 전에 패키저가 playlist 를 덮어쓴다
+	release := make(chan struct{})
+	fake.fail = func(r *http.Request) int {
+		if strings.HasSuffix(r.URL.Path, ".ts") {
+			<-release
+		}
+		return 0
+	}
+
+	dir := t.TempDir()
+	segment := filepath.Join(dir, "seg-001.ts")
+	playlist := filepath.Join(dir, "index.m3u8")
+	os.WriteFile(segment, []byte("ts"), 0o644)
+	os.WriteFile(playlist, []byte("#EXTM3U\nseg-001.ts\n"), 0o644)
+
+	sink := store.SegmentSink("bucket", "videos/42/")
+	sink.AddSegment("seg-001.ts", segment)
+	sink.AddPlaylist("index.m3u8", playlist)
+	os.WriteFile(playlist, []byte("#EXTM3U\nseg-001.ts\nseg-002.ts\n"), 0o644)
+	close(release)
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fake.get("bucket", "videos/42/index.m3u8")); got != "#EXTM3U\nseg-001.ts\n" {
+		t.Fatalf("uploaded playlist %q", got)
+	}
+
+	// 읽을 수 없는 playlist 는 바로 오류
+	sink = store.SegmentSink("bucket", "videos/42/")
+	sink.AddPlaylist("missing.m3u8", filepath.Join(dir, "missing.m3u8"))
+	if err := sink.Close(); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// NextTokenHeader 다음 페이지 ContinuationToken, 마지막 페이지면 없음
+const NextTokenHeader = "X-Next-Token"
+
+type ServeListOptions struct {
+	Limit int    // default: 1000
+	Token string // 이전 응답의 X-Next-Token
+	Array bool   // true: JSON 배열, false: NDJSON
+}
+
+// ServeList 는 목록 한 페이지를 버퍼링 없이 w 에 바로 인코딩한다.
+// 다음 페이지 토큰은 X-Next-Token 헤더로 전달한다.
+// 목록 조회에 실패하면 아무것도 쓰지 않고 오류를 반환하므로 호출자가 상태 코드를 정한다.
+func (s *Storage) ServeList(w http.ResponseWriter, bucket, prefix string, opts ServeListOptions) error {
+	if opts.Limit <= 0 {
+		opts.Limit = 1000
+	}
+
+	var token []string
+	if opts.Token != "" {
+		token = []string{opts.Token}
+	}
+
+	list, next, err := s.ListInto(nil, bucket, prefix, opts.Limit, token...)
+	if err != nil {
+		return err
+	}
+
+	if opts.Array {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	if next != "" {
+		w.Header().Set(NextTokenHeader, next)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	if opts.Array {
+		w.Write([]byte("["))
+	}
+
+	for i, obj := range list {
+		if opts.Array && i > 0 {
+			w.Write([]byte(","))
+		}
+
+		if err = encoder.Encode(obj); err != nil {
+			return err
+		}
+
+		if flusher != nil && (i+1)%100 == 0 {
+			flusher.Flush()
+		}
+	}
+
+	if opts.Array {
+		w.Write([]byte("]"))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeList(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	for i := range 5 {
+		fake.put("bucket", fmt.Sprintf("logs/%d.txt", i), []byte("abc"))
+	}
+
+	// NDJSON, 첫 페이지
+	rec := httptest.NewRecorder()
+	if err := store.ServeList(rec, "bucket", "logs/", ServeListOptions{Limit: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("content type %s", rec.Header().Get("Content-Type"))
+	}
+	var keys []string
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var obj ObjectInfo
+		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj.Size != 3 {
+			t.Errorf("%+v", obj)
+		}
+		keys = append(keys, obj.Key)
+	}
+	next := rec.Header().Get(NextTokenHeader)
+	if fmt.Sprint(keys) != "[logs/0.txt logs/1.txt logs/2.txt]" || next == "" {
+		t.Fatalf("keys %v, next %q", keys, next)
+	}
+
+	// JSON 배열, 다음 페이지는 마지막이므로 토큰 없음
+	rec = httptest.NewRecorder()
+	if err := store.ServeList(rec, "bucket", "logs/", ServeListOptions{Limit: 3, Token: next, Array: true}); err != nil {
+		t.Fatal(err)
+	}
+	var page []ObjectInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("%v: %s", err, rec.Body)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" || len(page) != 2 || page[0].Key != "logs/3.txt" {
+		t.Errorf("page %+v", page)
+	}
+	if _, ok := rec.Header()[NextTokenHeader]; ok {
+		t.Error("마지막 페이지에 토큰이 있음")
+	}
+
+	// 빈 목록도 올바른 JSON 배열
+	rec = httptest.NewRecorder()
+	if err := store.ServeList(rec, "bucket", "none/", ServeListOptions{Array: true}); err != nil || rec.Body.String() != "[]" {
+		t.Errorf("empty %q, %v", rec.Body, err)
+	}
+}
+
+func TestServeListError(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	fake.fail = func(*http.Request) int { return http.StatusForbidden }
+
+	// 오류면 아무것도 쓰지 않으므로 호출자가 상태 코드를 정할 수 있다
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := store.ServeList(w, "bucket", "", ServeListOptions{}); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrAccessDenied) {
+				status = http.StatusForbidden
+			}
+			http.Error(w, "list failed", status)
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Content-Type") != "text/plain; charset=utf-8" || rec.Body.String() != "list failed\n" {
+		t.Errorf("status %d, headers %v, body %q", rec.Code, rec.Header(), rec.Body)
+	}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var ErrSessionIncomplete = errors.New("upload session incomplete")
+
+// PlannedFile 은 업로드 세션에 포함할 파일
+type PlannedFile struct {
+	Key    string `json:"key"`
+	Size   int64  `json:"size,omitempty"`   // 0 이면 검사하지 않음
+	SHA256 string `json:"sha256,omitempty"` // VerifyAndReceipt 에서만 검사
+}
+
+// UploadSession 은 여러 파일을 한 번에 올리기 위해 발급한 ticket 묶음.
+// 앱이 ID 로 저장해 두었다가 클라이언트가 완료를 알리면 FinalizeSession 에 넘긴다.
+type UploadSession struct {
+	ID        string          `json:"id"`
+	Bucket    string          `json:"bucket"`
+	Tickets   []*UploadTicket `json:"tickets"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// IncompleteSessionError 는 FinalizeSession 에서 도착하지 않았거나 기대값과 다른 파일 목록.
+// errors.Is(err, ErrSessionIncomplete) 도 true.
+type IncompleteSessionError struct {
+	ID      string
+	Missing []string         // 올라오지 않은 key
+	Invalid map[string]error // key → *MismatchError, ErrTicketExpired 등
+}
+
+func (e *IncompleteSessionError) Error() string {
+	return fmt.Sprintf("upload session %s incomplete: %d missing, %d invalid", e.ID, len(e.Missing), len(e.Invalid))
+}
+
+func (e *IncompleteSessionError) Is(target error) bool {
+	return target == ErrSessionIncomplete
+}
+
+// CreateUploadSession 은 files 마다 ttl 동안 유효한 presigned PUT ticket 을 발급하고 세션 ID 로 묶는다.
+// 모바일 앱처럼 여러 파일을 한 번에 올리는 클라이언트가 URL 을 파일마다 따로 요청하지 않게 한다.
+func (s *Storage) CreateUploadSession(bucket string, files []PlannedFile, ttl time.Duration) (*UploadSession, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files")
+	}
+
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		if seen[file.Key] {
+			return nil, fmt.Errorf("duplicate key %s", file.Key)
+		}
+		seen[file.Key] = true
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	session := &UploadSession{
+		ID:      hex.EncodeToString(id),
+		Bucket:  bucket,
+		Tickets: make([]*UploadTicket, 0, len(files)),
+	}
+	for _, file := range files {
+		ticket, err := s.IssueUploadTicket(bucket, file.Key, ttl, Artifact{Size: file.Size, SHA256: strings.ToLower(file.SHA256)})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Key, err)
+		}
+		session.Tickets = append(session.Tickets, ticket)
+		session.ExpiresAt = ticket.ExpiresAt
+	}
+	return session, nil
+}
+
+// FinalizeSession 은 세션의 모든 파일이 올라왔는지 HEAD 로 확인한다. 크기와 업로드 시각(발급 후, 만료 전)도 확인한다.
+// 세션을 만들기 전부터 있던 객체는 올라온 것으로 보지 않는다 (ErrNotUploaded).
+// 하나라도 빠졌거나 다르면 *IncompleteSessionError 를 반환하며, 클라이언트는 해당 파일만 다시 올리면 된다.
+// 내용(SHA-256)까지 확인하려면 ticket 마다 VerifyAndReceipt 를 사용한다.
+func (s *Storage) FinalizeSession(session *UploadSession) error {
+	incomplete := &IncompleteSessionError{ID: session.ID, Invalid: map[string]error{}}
+
+	for _, ticket := range session.Tickets {
+		head, err := s.Info(ticket.Bucket, ticket.Key)
+		if isNotFound(err) {
+			incomplete.Missing = append(incomplete.Missing, ticket.Key)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = ticket.check(aws.ToInt64(head.ContentLength), aws.ToTime(head.LastModified)); err != nil {
+			incomplete.Invalid[ticket.Key] = err
+		}
+	}
+
+	if len(incomplete.Missing) > 0 || len(incomplete.Invalid) > 0 {
+		return incomplete
+	}
+	return nil
+}
+
+// check 는 올라온 객체의 크기와 업로드 시각을 ticket 과 비교한다.
+func (t *UploadTicket) check(size int64, uploadedAt time.Time) error {
+	if t.preexisting(uploadedAt) {
+		return ErrNotUploaded
+	}
+	if t.expired(uploadedAt) {
+		return ErrTicketExpired
+	}
+	if t.Size > 0 && size != t.Size {
+		return &MismatchError{Key: t.Key, Field: "size", Expected: fmt.Sprint(t.Size), Actual: fmt.Sprint(size)}
+	}
+	return nil
+}
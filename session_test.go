@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTicketCheck(t *testing.T) {
+	expires := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	issued := expires.Add(-time.Hour).Add(300 * time.Millisecond)
+	ticket := &UploadTicket{Key: "a.jpg", Size: 100, IssuedAt: issued, ExpiresAt: expires}
+
+	if err := ticket.check(100, expires.Add(-time.Minute)); err != nil {
+		t.Error(err)
+	}
+	// Last-Modified 초 단위 반올림 허용
+	if err := ticket.check(100, expires.Add(time.Second)); err != nil {
+		t.Error(err)
+	}
+	if err := ticket.check(100, expires.Add(time.Minute)); !errors.Is(err, ErrTicketExpired) {
+		t.Error(err)
+	}
+	// 발급 직후 올라온 객체의 Last-Modified 는 발급 시각보다 이를 수 있음
+	if err := ticket.check(100, issued.Truncate(time.Second)); err != nil {
+		t.Error(err)
+	}
+	// 발급 전부터 있던 객체
+	if err := ticket.check(100, issued.Add(-time.Minute)); !errors.Is(err, ErrNotUploaded) {
+		t.Error(err)
+	}
+
+	var mismatch *MismatchError
+	if err := ticket.check(99, expires); !errors.As(err, &mismatch) || mismatch.Field != "size" {
+		t.Error(err)
+	}
+}
+
+func TestIncompleteSessionError(t *testing.T) {
+	var err error = &IncompleteSessionError{ID: "s1", Missing: []string{"b.jpg"}, Invalid: map[string]error{}}
+	if !errors.Is(err, ErrSessionIncomplete) || err.Error() != "upload session s1 incomplete: 1 missing, 0 invalid" {
+		t.Error(err)
+	}
+}
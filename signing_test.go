@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSigningRegion(t *testing.T) {
+	cases := []struct {
+		signingRegion string
+		want          string
+	}{
+		{"", "/us-east-1/s3/aws4_request"},
+		{"eu-central-1", "/eu-central-1/s3/aws4_request"},
+	}
+	for _, c := range cases {
+		store, fake := newFakeStorage(t, Config{Region: "us-east-1", SigningRegion: c.signingRegion})
+		fake.put("bucket", "a.txt", []byte("a"))
+
+		var (
+			mu    sync.Mutex
+			auths []string
+		)
+		fake.fail = func(r *http.Request) int {
+			mu.Lock()
+			auths = append(auths, r.Header.Get("Authorization"))
+			mu.Unlock()
+			return 0
+		}
+
+		if _, err := store.InfoObject("bucket", "a.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if len(auths) == 0 || !strings.Contains(auths[0], c.want) {
+			t.Errorf("SigningRegion %q: Authorization %q", c.signingRegion, auths)
+		}
+
+		// presign 도 같은 region 으로 서명
+		presigned, err := store.PresignGet("bucket", "a.txt", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := url.Parse(presigned)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if credential := u.Query().Get("X-Amz-Credential"); !strings.HasSuffix(credential, c.want) {
+			t.Errorf("SigningRegion %q: presign credential %q", c.signingRegion, credential)
+		}
+	}
+}
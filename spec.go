@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// BucketSpec 은 버킷의 원하는 상태. nil 필드는 관리하지 않고, 비어 있는(non-nil) 값은 해당 설정을 삭제한다.
+// JSON / YAML 에서는 필드를 생략하면 nil, [] 또는 {} 로 쓰면 빈 값이 된다.
+type BucketSpec struct {
+	Bucket        string            `json:"bucket" yaml:"bucket"`
+	CORS          []CORSRule        `json:"cors,omitempty" yaml:"cors,omitempty"`
+	Lifecycle     []LifecycleRule   `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Notifications []Notification    `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+}
+
+type CORSRule struct {
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+	AllowedMethods []string `json:"allowed_methods" yaml:"allowed_methods"`
+	AllowedHeaders []string `json:"allowed_headers,omitempty" yaml:"allowed_headers,omitempty"`
+	ExposeHeaders  []string `json:"expose_headers,omitempty" yaml:"expose_headers,omitempty"`
+	MaxAgeSeconds  int32    `json:"max_age_seconds,omitempty" yaml:"max_age_seconds,omitempty"`
+}
+
+// LifecycleRule 은 prefix 아래 객체의 만료 규칙. 0 인 기간은 설정하지 않는다.
+type LifecycleRule struct {
+	ID                  string `json:"id" yaml:"id"`
+	Prefix              string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	ExpireDays          int32  `json:"expire_days,omitempty" yaml:"expire_days,omitempty"`
+	AbortIncompleteDays int32  `json:"abort_incomplete_days,omitempty" yaml:"abort_incomplete_days,omitempty"` // 미완료 multipart 정리
+	Disabled            bool   `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// SpecDrift 는 spec 과 다른 설정 하나
+type SpecDrift struct {
+	Field   string `json:"field"` // "bucket", "cors", "lifecycle", "tags", "notifications"
+	Current any    `json:"current"`
+	Desired any    `json:"desired"`
+}
+
+type SpecReport struct {
+	Bucket  string      `json:"bucket"`
+	Drift   []SpecDrift `json:"drift,omitempty"`
+	Applied bool        `json:"applied"` // dry-run 이면 false
+}
+
+// ApplyBucketSpec 은 버킷을 spec 과 비교해 다른 설정을 보고하고, dryRun 이 아니면 spec 대로 맞춘다.
+// 버킷이 없으면 만들고, 각 설정은 부분 수정 없이 spec 의 값으로 통째로 교체한다.
+// spec 으로 표현할 수 없는 설정(전환 규칙 등)이 있는 lifecycle 도 다르다고 보고 교체된다.
+// 적용 중 실패하면 그때까지의 보고서와 오류를 반환한다.
+func (s *Storage) ApplyBucketSpec(spec BucketSpec, dryRun ...bool) (*SpecReport, error) {
+	report := &SpecReport{Bucket: spec.Bucket, Applied: len(dryRun) == 0 || !dryRun[0]}
+
+	exists, err := s.BucketExists(spec.Bucket)
+	if err != nil {
+		return report, err
+	}
+	if !exists {
+		report.Drift = append(report.Drift, SpecDrift{Field: "bucket", Current: false, Desired: true})
+		if report.Applied {
+			if err = s.CreateBucket(spec.Bucket); err != nil {
+				return report, err
+			}
+			exists = true
+		}
+	}
+
+	// 버킷이 없는 dry-run 은 모든 설정이 비어 있는 것으로 비교
+	reconcile := func(field string, managed bool, get func() (any, error), desired any, put func() error) error {
+		if !managed {
+			return nil
+		}
+
+		var current any
+		if exists {
+			value, err := get()
+			if err != nil {
+				return err
+			}
+			current = value
+		}
+		if sameJSON(current, desired) {
+			return nil
+		}
+
+		report.Drift = append(report.Drift, SpecDrift{Field: field, Current: current, Desired: desired})
+		if !report.Applied {
+			return nil
+		}
+		return put()
+	}
+
+	err = reconcile("cors", spec.CORS != nil,
+		func() (any, error) { return s.bucketCORS(spec.Bucket) }, spec.CORS,
+		func() error { return s.putBucketCORS(spec.Bucket, spec.CORS) })
+	if err != nil {
+		return report, err
+	}
+
+	err = reconcile("lifecycle", spec.Lifecycle != nil,
+		func() (any, error) { return s.bucketLifecycle(spec.Bucket) }, spec.Lifecycle,
+		func() error { return s.putBucketLifecycle(spec.Bucket, spec.Lifecycle) })
+	if err != nil {
+		return report, err
+	}
+
+	err = reconcile("tags", spec.Tags != nil,
+		func() (any, error) { return s.bucketTags(spec.Bucket) }, spec.Tags,
+		func() error { return s.putBucketTags(spec.Bucket, spec.Tags) })
+	if err != nil {
+		return report, err
+	}
+
+	err = reconcile("notifications", spec.Notifications != nil,
+		func() (any, error) { return s.GetBucketNotification(spec.Bucket) }, spec.Notifications,
+		func() error { return s.PutBucketNotification(spec.Bucket, spec.Notifications...) })
+	return report, err
+}
+
+// sameJSON 은 nil 과 빈 값을 같게 보고 비교한다.
+func sameJSON(a, b any) bool {
+	normalize := func(v any) string {
+		data, _ := json.Marshal(v)
+		switch string(data) {
+		case "null", "[]", "{}":
+			return ""
+		}
+		return string(data)
+	}
+	return normalize(a) == normalize(b)
+}
+
+func (s *Storage) bucketCORS(bucket string) ([]CORSRule, error) {
+	output, err := s.client.GetBucketCors(s.requestContext(), &s3.GetBucketCorsInput{Bucket: aws.String(bucket)})
+	if errorCode(err) == "NoSuchCORSConfiguration" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]CORSRule, 0, len(output.CORSRules))
+	for _, rule := range output.CORSRules {
+		rules = append(rules, CORSRule{
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  aws.ToInt32(rule.MaxAgeSeconds),
+		})
+	}
+	return rules, nil
+}
+
+func (s *Storage) putBucketCORS(bucket string, rules []CORSRule) error {
+	if len(rules) == 0 {
+		_, err := s.client.DeleteBucketCors(s.requestContext(), &s3.DeleteBucketCorsInput{Bucket: aws.String(bucket)})
+		return err
+	}
+
+	configuration := &types.CORSConfiguration{}
+	for _, rule := range rules {
+		cors := types.CORSRule{
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+		}
+		if rule.MaxAgeSeconds > 0 {
+			cors.MaxAgeSeconds = aws.Int32(rule.MaxAgeSeconds)
+		}
+		configuration.CORSRules = append(configuration.CORSRules, cors)
+	}
+
+	_, err := s.client.PutBucketCors(s.requestContext(), &s3.PutBucketCorsInput{
+		Bucket:            aws.String(bucket),
+		CORSConfiguration: configuration,
+	})
+	return err
+}
+
+func (s *Storage) bucketLifecycle(bucket string) ([]LifecycleRule, error) {
+	output, err := s.client.GetBucketLifecycleConfiguration(s.requestContext(), &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucket)})
+	if errorCode(err) == "NoSuchLifecycleConfiguration" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]LifecycleRule, 0, len(output.Rules))
+	for _, rule := range output.Rules {
+		rules = append(rules, lifecycleRule(rule))
+	}
+	return rules, nil
+}
+
+// lifecycleRule 은 SDK 규칙을 변환한다. 구버전 Prefix 필드도 읽는다.
+func lifecycleRule(rule types.LifecycleRule) LifecycleRule {
+	r := LifecycleRule{
+		ID:       aws.ToString(rule.ID),
+		Prefix:   aws.ToString(rule.Prefix),
+		Disabled: rule.Status == types.ExpirationStatusDisabled,
+	}
+	if rule.Filter != nil && rule.Filter.Prefix != nil {
+		r.Prefix = *rule.Filter.Prefix
+	}
+	if rule.Expiration != nil {
+		r.ExpireDays = aws.ToInt32(rule.Expiration.Days)
+	}
+	if rule.AbortIncompleteMultipartUpload != nil {
+		r.AbortIncompleteDays = aws.ToInt32(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+	}
+	return r
+}
+
+func (s *Storage) putBucketLifecycle(bucket string, rules []LifecycleRule) error {
+	if len(rules) == 0 {
+		_, err := s.client.DeleteBucketLifecycle(s.requestContext(), &s3.DeleteBucketLifecycleInput{Bucket: aws.String(bucket)})
+		return err
+	}
+
+	configuration := &types.BucketLifecycleConfiguration{}
+	for _, rule := range rules {
+		lifecycle := types.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Status: types.ExpirationStatusEnabled,
+			Filter: &types.LifecycleRuleFilter{Prefix: aws.String(rule.Prefix)},
+		}
+		if rule.Disabled {
+			lifecycle.Status = types.ExpirationStatusDisabled
+		}
+		if rule.ExpireDays > 0 {
+			lifecycle.Expiration = &types.LifecycleExpiration{Days: aws.Int32(rule.ExpireDays)}
+		}
+		if rule.AbortIncompleteDays > 0 {
+			lifecycle.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int32(rule.AbortIncompleteDays)}
+		}
+		configuration.Rules = append(configuration.Rules, lifecycle)
+	}
+
+	_, err := s.client.PutBucketLifecycleConfiguration(s.requestContext(), &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: configuration,
+	})
+	return err
+}
+
+func (s *Storage) bucketTags(bucket string) (map[string]string, error) {
+	output, err := s.client.GetBucketTagging(s.requestContext(), &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)})
+	if errorCode(err) == "NoSuchTagSet" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+func (s *Storage) putBucketTags(bucket string, tags map[string]string) error {
+	if len(tags) == 0 {
+		_, err := s.client.DeleteBucketTagging(s.requestContext(), &s3.DeleteBucketTaggingInput{Bucket: aws.String(bucket)})
+		return err
+	}
+
+	tagging := &types.Tagging{}
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		tagging.TagSet = append(tagging.TagSet, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	_, err := s.client.PutBucketTagging(s.requestContext(), &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucket),
+		Tagging: tagging,
+	})
+	return err
+}
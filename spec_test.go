@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestSameJSON(t *testing.T) {
+	var none []CORSRule
+	cors := []CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}}
+
+	cases := []struct {
+		a, b any
+		want bool
+	}{
+		{none, []CORSRule{}, true},
+		{nil, map[string]string{}, true},
+		{cors, []CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{}}}, true},
+		{cors, none, false},
+		{map[string]string{"env": "prod", "team": "a"}, map[string]string{"team": "a", "env": "prod"}, true},
+		{map[string]string{"env": "prod"}, map[string]string{"env": "dev"}, false},
+	}
+	for i, c := range cases {
+		if got := sameJSON(c.a, c.b); got != c.want {
+			t.Errorf("%d: %v", i, got)
+		}
+	}
+}
+
+func TestLifecycleRule(t *testing.T) {
+	got := lifecycleRule(types.LifecycleRule{
+		ID:                             aws.String("tmp"),
+		Status:                         types.ExpirationStatusEnabled,
+		Filter:                         &types.LifecycleRuleFilter{Prefix: aws.String("tmp/")},
+		Expiration:                     &types.LifecycleExpiration{Days: aws.Int32(7)},
+		AbortIncompleteMultipartUpload: &types.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int32(1)},
+	})
+	if got != (LifecycleRule{ID: "tmp", Prefix: "tmp/", ExpireDays: 7, AbortIncompleteDays: 1}) {
+		t.Errorf("%+v", got)
+	}
+
+	// 구버전 Prefix 필드
+	got = lifecycleRule(types.LifecycleRule{ID: aws.String("old"), Prefix: aws.String("logs/"), Status: types.ExpirationStatusDisabled})
+	if got != (LifecycleRule{ID: "old", Prefix: "logs/", Disabled: true}) {
+		t.Errorf("%+v", got)
+	}
+}
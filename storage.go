@@ -1,19 +1,28 @@
 package storage
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config" // "config" 충돌 방지 위해 별칭 사용
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/pro200/go-utils"
 )
 
@@ -22,11 +31,54 @@ type Config struct {
 	Region          string // default: auto
 	AccessKeyID     string
 	SecretAccessKey string
+	Policy          *Policy           // nil 이면 제한 없음
+	PublicBaseURL   string            // CDN / 공개 도메인, 예: https://cdn.example.com
+	Faults          *FaultInjector    // staging 장애 주입
+	Transport       http.RoundTripper // 예: NewRecorder(dir, Replay)
+	DialContext     DialFunc          // 예: StaticHosts(...), Transport 를 지정하면 무시
+	Proxy           string            // 예: socks5://127.0.0.1:1080, http://proxy:3128, Transport 를 지정하면 무시
+	EndpointRules   []EndpointRule    // 작업별 endpoint, 예: 읽기는 지역 mirror 로
+	Require         []Capability      // 지원하지 않으면 New 에서 실패
+	Hedge           *Hedge            // GET/HEAD 지연 시 중복 요청
+	TruncateKeys    bool              // 1024 bytes 를 넘는 key 를 해시를 붙여 줄임
+	CreatedBy       string            // 업로드 객체의 created-by 메타데이터, 예: 서비스 이름
+	DirStats        bool              // 쓰기/삭제 시 디렉터리별 통계 객체(.dirstats.json) 갱신
+	Blackouts       []Blackout        // 대량 작업을 멈추는 시간대
+	GuardedDelete   bool              // DeletePrefix 는 Confirm 후에만 삭제
+	Prober          Prober            // 로컬 파일 업로드 시 가로/세로, 길이를 메타데이터로 저장
+	PresignTTL      TTLPolicy         // presigned URL 유효 기간 범위
+
+	// Upload / Download multipart 설정, 0 이면 SDK 기본값(5 MiB, 5, 10000)
+	PartSize       int64 // part 크기, 최소 5 MiB
+	Concurrency    int   // 동시에 전송할 part 수
+	MaxUploadParts int32 // 업로드 최대 part 수, 넘으면 part 크기를 늘림
+
+	// 재시도, 0 이면 SDK 기본(3), RetryRule 이 있으면 8
+	MaxAttempts int
+	RetryRules  []RetryRule // provider 기본 규칙(B2 등) 뒤에 추가
+
+	// S3 호환 장비(on-prem gateway 등)용
+	SigningRegion string                    // 서명에 사용할 region, 비어 있으면 Region
+	SignerOptions []func(*v4.SignerOptions) // SigV4 서명 옵션
+	S3Options     []func(*s3.Options)       // 그 밖의 SDK client 옵션, 마지막에 적용
 }
 
 type Options struct {
-	Headers     map[string]string
-	ContentType string
+	Headers      map[string]string
+	ContentType  string
+	Checksum     ChecksumAlgorithm // 비어 있으면 SDK 기본 동작
+	CacheControl string
+	StorageClass string    // 예: STANDARD_IA, GLACIER (스토리지마다 다름)
+	ExpiresAt    time.Time // 지나면 SweepExpired 가 삭제
+	PartSize     int64     // 0 이면 Config.PartSize
+	Concurrency  int       // 0 이면 Config.Concurrency
+
+	Metadata           map[string]string // x-amz-meta-*, key 는 소문자로 저장됨
+	ContentDisposition string            // 예: attachment; filename="a.pdf"
+	ContentEncoding    string            // 예: gzip
+	ContentLanguage    string
+
+	progress func(transferred, total int64) // WithProgress
 }
 
 type SType string
@@ -35,6 +87,13 @@ type Storage struct {
 	config        Config
 	client        *s3.Client
 	presignClient *s3.PresignClient
+	uploader      *manager.Uploader // Config 의 multipart 설정 적용, 호출별 옵션은 Upload 시 적용
+	downloader    *manager.Downloader
+	httpClient    *http.Client // 원격 원본, 공개 URL 요청용
+	meter         *meter
+	profiles      *profiles       // bucket 별 기본 업로드 옵션
+	skew          *clockSkew      // 서버 시각 차이, 서명 시각 보정
+	ctx           context.Context // WithContext, nil 이면 context.TODO()
 }
 
 func New(config Config) (*Storage, error) {
@@ -55,51 +114,144 @@ func New(config Config) (*Storage, error) {
 		config.Region = "auto"
 	}
 
-	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(),
+	if err := checkCapabilities(detectType(config.Endpoint), config.Require); err != nil {
+		return nil, err
+	}
+
+	loadOptions := []func(*awsConfig.LoadOptions) error{
 		awsConfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(config.AccessKeyID, config.SecretAccessKey, "")),
 		awsConfig.WithRegion(config.Region),
-	)
+	}
+
+	transport, err := config.transport()
 	if err != nil {
 		return nil, err
 	}
 
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+	httpClient := http.DefaultClient
+	if transport != nil {
+		httpClient = &http.Client{Transport: transport}
+		loadOptions = append(loadOptions, awsConfig.WithHTTPClient(httpClient))
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(), loadOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := &Storage{
+		config:     config,
+		httpClient: httpClient,
+		meter:      newMeter(),
+		profiles:   &profiles{},
+		skew:       &clockSkew{},
+	}
+
+	// S3 기본 서명 옵션과 같이 경로를 다시 escape 하지 않음
+	signer := &skewSigner{
+		signer: v4.NewSigner(append([]func(*v4.SignerOptions){func(so *v4.SignerOptions) {
+			so.DisableURIPathEscaping = true
+		}}, config.SignerOptions...)...),
+		skew: storage.skew,
+	}
+
+	storage.client = s3.NewFromConfig(cfg, func(o *s3.Options) {
 		o.BaseEndpoint = aws.String(config.Endpoint)
+		o.APIOptions = append(o.APIOptions, storage.meter.addMiddleware, addBudgetMiddleware, addErrorMiddleware, storage.skew.addMiddleware)
+		if config.Faults != nil {
+			o.APIOptions = append(o.APIOptions, config.Faults.addMiddleware)
+		}
+		if config.SigningRegion != "" {
+			o.Region = config.SigningRegion
+		}
+		o.HTTPSignerV4 = signer
+		rules := append(slices.Clone(providerRetryRules[detectType(config.Endpoint)]), config.RetryRules...)
+		if len(rules) > 0 || config.MaxAttempts > 0 {
+			o.Retryer = newRetryer(rules, config.MaxAttempts)
+		}
+		if len(config.EndpointRules) > 0 {
+			o.EndpointResolverV2 = newEndpointResolver(config.EndpointRules)
+		}
+		for _, fn := range config.S3Options {
+			fn(o)
+		}
+	})
+	storage.presignClient = s3.NewPresignClient(storage.client, func(o *s3.PresignOptions) {
+		o.Presigner = signer
 	})
+	storage.uploader = manager.NewUploader(storage.client, config.uploaderOptions)
+	storage.downloader = manager.NewDownloader(storage.client, config.downloaderOptions)
 
-	return &Storage{
-		config:        config,
-		client:        client,
-		presignClient: s3.NewPresignClient(client),
-	}, nil
+	return storage, nil
 }
 
-func (s *Storage) Info(bucket, key string) (*s3.HeadObjectOutput, error) {
-	return s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-	})
+// WithContext 는 ctx 로 요청하는 Storage 사본을 반환한다. 설정과 client 는 원본과 공유한다.
+// ctx 에 deadline 이 있으면 시간 초과 시 *DeadlineBudgetError 를 반환한다.
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+//	defer cancel()
+//	err := store.WithContext(ctx).Upload(bucket, key, path)
+func (s *Storage) WithContext(ctx context.Context) *Storage {
+	c := *s
+	c.ctx = ctx
+	return &c
 }
 
-func (s *Storage) List(bucket, prefix string, length int, token ...string) (list []string, nextToken string, err error) {
-	// up to 1,000 keys
-	if length > 1000 {
-		length = 1000
+func (s *Storage) requestContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.TODO()
+}
+
+func (s *Storage) Info(bucket, key string) (*s3.HeadObjectOutput, error) {
+	key, err := s.prepareKey(OpInfo, key)
+	if err != nil {
+		return nil, err
 	}
 
-	options := s3.ListObjectsV2Input{
-		Bucket:  aws.String(bucket),
-		Prefix:  aws.String(prefix),
-		MaxKeys: aws.Int32(int32(length)),
+	return hedged(s.requestContext(), s.config.Hedge, func(ctx context.Context) (*s3.HeadObjectOutput, error) {
+		return s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	}, nil, nil)
+}
+
+// InfoObject 는 Info 와 같지만 SDK 타입 대신 ObjectInfo 를 반환한다.
+func (s *Storage) InfoObject(bucket, key string) (ObjectInfo, error) {
+	output, err := s.Info(bucket, key)
+	if err != nil {
+		return ObjectInfo{}, err
 	}
 
-	// ContinuationToken
-	// A token to specify where to start paginating. This is the NextContinuationToken from a previously truncated response.
-	if len(token) > 0 {
-		options.ContinuationToken = aws.String(token[0])
+	key, _ = s.prepareKey(OpInfo, key)
+	return ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		ETag:         strings.Trim(aws.ToString(output.ETag), `"`),
+		LastModified: aws.ToTime(output.LastModified),
+		StorageClass: string(output.StorageClass),
+		ContentType:  aws.ToString(output.ContentType),
+		Metadata:     output.Metadata,
+		Note:         decodeNote(output.Metadata[metaNote]),
+	}, nil
+}
+
+// Exists 객체가 없으면(404, NoSuchKey) false, 그 밖의 오류는 그대로 반환
+func (s *Storage) Exists(bucket, key string) (bool, error) {
+	_, err := s.Info(bucket, key)
+	if isNotFound(err) {
+		return false, nil
 	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	output, err := s.client.ListObjectsV2(context.TODO(), &options)
+func (s *Storage) List(bucket, prefix string, length int, token ...string) (list []string, nextToken string, err error) {
+	output, err := s.listObjects(bucket, prefix, listOptions(length, token))
 	if err != nil {
 		return list, nextToken, err
 	}
@@ -112,30 +264,35 @@ func (s *Storage) List(bucket, prefix string, length int, token ...string) (list
 	return list, nextToken, nil
 }
 
-func (s *Storage) Upload(bucket, key, origin string, options ...Options) error {
+func (s *Storage) Upload(bucket, key, origin string, options ...UploadOption) error {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return err
+	}
+
 	var (
-		err      error
 		resp     *http.Response
 		file     *os.File
 		size     int
 		isRemote = strings.HasPrefix(origin, "https://")
 	)
 
-	var opt = new(Options)
-	if len(options) > 0 {
-		opt = &options[0]
+	opt := s.options(bucket, options...)
+
+	if err = s.checkChecksum(opt.Checksum); err != nil {
+		return err
 	}
 
 	// remote 파일 스트림
 	if isRemote {
-		req, _ := http.NewRequest("GET", origin, nil)
+		req, _ := http.NewRequestWithContext(s.requestContext(), "GET", origin, nil)
 
 		// set headers
 		for key, value := range opt.Headers {
 			req.Header.Set(key, value)
 		}
 
-		resp, err = http.DefaultClient.Do(req)
+		resp, err = s.httpClient.Do(req)
 		if err != nil {
 			return err
 		}
@@ -167,30 +324,204 @@ func (s *Storage) Upload(bucket, key, origin string, options ...Options) error {
 		opt.ContentType = utils.ContentType(origin)
 	}
 
+	// 빈 파일은 multipart uploader 를 거치지 않고 PutObject 한 번으로
 	if size == 0 {
-		return errors.New("zero size file")
+		return s.putEmpty(bucket, key, opt)
 	}
 
-	putObject := &s3.PutObjectInput{
-		Bucket:      aws.String(bucket),
-		Key:         aws.String(key),
-		Body:        file,
-		ContentType: aws.String(opt.ContentType),
-	}
+	putObject := s.putObjectInput(bucket, key, opt)
 
 	// remote url
 	if isRemote {
 		putObject.Body = resp.Body
+	} else {
+		putObject.Body = NewRetryingReader(file)
+
+		for name, value := range s.probeMetadata(file, int64(size), opt.ContentType) {
+			if putObject.Metadata == nil {
+				putObject.Metadata = map[string]string{}
+			}
+			putObject.Metadata[name] = value
+		}
+	}
+
+	var md5Hash hash.Hash
+	if opt.Checksum == ChecksumMD5 {
+		md5Hash = md5.New()
+		if isRemote {
+			putObject.Body = io.TeeReader(resp.Body, md5Hash)
+		} else {
+			if _, err = io.Copy(md5Hash, file); err != nil {
+				return err
+			}
+			if _, err = file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opt.progress != nil {
+		p := &progress{total: int64(size), fn: opt.progress}
+		if body, ok := putObject.Body.(readerAtSeeker); ok {
+			putObject.Body = &progressReaderAt{readerAtSeeker: body, p: p}
+		} else {
+			putObject.Body = &progressReader{r: putObject.Body, p: p}
+		}
 	}
 
-	uploader := manager.NewUploader(s.client)
-	_, err = uploader.Upload(context.TODO(), putObject)
+	previous := s.sizeBefore(bucket, key)
+
+	_, err = s.uploader.Upload(s.requestContext(), putObject, opt.uploaderOptions)
+	if err != nil {
+		return err
+	}
+
+	if err = s.verifyUpload(bucket, key, int64(size), md5Hash); err != nil {
+		return err
+	}
+
+	s.updateDirStats(bucket, key, previous, int64(size))
+	return nil
+}
+
+// UploadReader 길이를 알 수 없는 스트림(pipe, 명령 출력, 네트워크)을 업로드
+// seek 할 수 없는 reader 는 part 단위로 버퍼링하여 multipart 로 올린다
+func (s *Storage) UploadReader(bucket, key string, r io.Reader, options ...UploadOption) error {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return err
+	}
+
+	opt := s.options(bucket, options...)
+
+	if err = s.checkChecksum(opt.Checksum); err != nil {
+		return err
+	}
+
+	// content type from key extension if not set
+	if opt.ContentType == "" {
+		opt.ContentType = utils.ContentType(key)
+	}
+
+	// 빈 스트림은 Upload 와 동일하게 거부
+	br := bufio.NewReader(r)
+	if _, err = br.Peek(1); err == io.EOF {
+		return errors.New("zero size file")
+	} else if err != nil {
+		return err
+	}
+
+	counter := &countingReader{r: br}
+	putObject := s.putObjectInput(bucket, key, opt)
+	putObject.Body = counter
+
+	var md5Hash hash.Hash
+	if opt.Checksum == ChecksumMD5 {
+		md5Hash = md5.New()
+		putObject.Body = io.TeeReader(counter, md5Hash)
+	}
+
+	if opt.progress != nil {
+		putObject.Body = &progressReader{r: putObject.Body, p: &progress{total: -1, fn: opt.progress}}
+	}
+
+	previous := s.sizeBefore(bucket, key)
+
+	_, err = s.uploader.Upload(s.requestContext(), putObject, opt.uploaderOptions)
+	if err != nil {
+		return err
+	}
+
+	if err = s.verifyUpload(bucket, key, counter.n, md5Hash); err != nil {
+		return err
+	}
+
+	s.updateDirStats(bucket, key, previous, counter.n)
+	return nil
+}
+
+// UploadReaderAt 은 r 의 [0, size) 를 업로드한다. multipart part 를 각 위치에서 동시에 읽으므로
+// 빠른 로컬 디스크(NVMe)나 mmap 원본에서 UploadReader 의 순차 읽기보다 빠르다.
+// r 은 동시 ReadAt 호출에 안전해야 한다 (*os.File, bytes.Reader 는 안전).
+func (s *Storage) UploadReaderAt(bucket, key string, r io.ReaderAt, size int64, options ...UploadOption) error {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return err
+	}
+
+	if size <= 0 {
+		return errors.New("zero size file")
+	}
+
+	opt := s.options(bucket, options...)
+
+	if err = s.checkChecksum(opt.Checksum); err != nil {
+		return err
+	}
+
+	if opt.ContentType == "" {
+		opt.ContentType = utils.ContentType(key)
+	}
+
+	section := io.NewSectionReader(r, 0, size)
+	putObject := s.putObjectInput(bucket, key, opt)
+	putObject.Body = section
+
+	var md5Hash hash.Hash
+	if opt.Checksum == ChecksumMD5 {
+		md5Hash = md5.New()
+		if _, err = io.Copy(md5Hash, section); err != nil {
+			return err
+		}
+		if _, err = section.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	if opt.progress != nil {
+		putObject.Body = &progressReaderAt{readerAtSeeker: section, p: &progress{total: size, fn: opt.progress}}
+	}
+
+	previous := s.sizeBefore(bucket, key)
+
+	_, err = s.uploader.Upload(s.requestContext(), putObject, opt.uploaderOptions)
 	if err != nil {
 		return err
 	}
 
-	// 업로드된 용량 비교
-	result, err := s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+	if err = s.verifyUpload(bucket, key, size, md5Hash); err != nil {
+		return err
+	}
+
+	s.updateDirStats(bucket, key, previous, size)
+	return nil
+}
+
+func (s *Storage) putObjectInput(bucket, key string, opt *Options) *s3.PutObjectInput {
+	putObject := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(opt.ContentType),
+	}
+
+	putObject.CacheControl = optionalString(opt.CacheControl)
+	putObject.ContentDisposition = optionalString(opt.ContentDisposition)
+	putObject.ContentEncoding = optionalString(opt.ContentEncoding)
+	putObject.ContentLanguage = optionalString(opt.ContentLanguage)
+	if opt.StorageClass != "" {
+		putObject.StorageClass = types.StorageClass(opt.StorageClass)
+	}
+	if opt.Checksum != "" && opt.Checksum != ChecksumMD5 {
+		putObject.ChecksumAlgorithm = types.ChecksumAlgorithm(opt.Checksum)
+	}
+	putObject.Metadata = s.objectMetadata(opt)
+
+	return putObject
+}
+
+// verifyUpload 업로드된 용량과 MD5(md5Hash 가 있을 때) 비교
+func (s *Storage) verifyUpload(bucket, key string, size int64, md5Hash hash.Hash) error {
+	result, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
@@ -199,42 +530,232 @@ func (s *Storage) Upload(bucket, key, origin string, options ...Options) error {
 	}
 
 	// TODO: 업로드 실패한 파일을 삭제
-	if size != int(*result.ContentLength) {
+	if size != aws.ToInt64(result.ContentLength) {
 		return errors.New("upload failed")
 	}
 
+	// multipart 업로드의 ETag("...-N")는 MD5 가 아니므로 비교하지 않음
+	etag := strings.Trim(aws.ToString(result.ETag), `"`)
+	if md5Hash != nil && !strings.Contains(etag, "-") && etag != hex.EncodeToString(md5Hash.Sum(nil)) {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, key)
+	}
+
 	return nil
 }
 
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func (s *Storage) Delete(bucket, key string) error {
-	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+	key, err := s.prepareKey(OpDelete, key)
+	if err != nil {
+		return err
+	}
+
+	previous := s.sizeBefore(bucket, key)
+
+	_, err = s.client.DeleteObject(s.requestContext(), &s3.DeleteObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	})
+	if err != nil {
+		return err
+	}
+
+	if previous >= 0 {
+		s.updateDirStats(bucket, key, previous, -1)
+	}
+	return nil
+}
+
+func (s *Storage) putBytes(bucket, key string, data []byte, contentType string) error {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return err
+	}
 
+	_, err = s.client.PutObject(s.requestContext(), &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String(contentType),
+	})
 	return err
 }
 
-func (s *Storage) Download(bucket, key, targetPath string) error {
-	fd, err := os.Create(targetPath)
+// putEmpty 는 0 바이트 객체를 만든다. 헤더와 메타데이터는 Upload 와 같이 적용한다.
+func (s *Storage) putEmpty(bucket, key string, opt *Options) error {
+	putObject := s.putObjectInput(bucket, key, opt)
+	putObject.Body = bytes.NewReader(nil)
+	putObject.ContentLength = aws.Int64(0)
+
+	previous := s.sizeBefore(bucket, key)
+	if _, err := s.client.PutObject(s.requestContext(), putObject); err != nil {
+		return err
+	}
+
+	s.updateDirStats(bucket, key, previous, 0)
+	return nil
+}
+
+// getObject 호출자가 Body 를 닫아야 한다
+func (s *Storage) getObject(bucket, key string) (*s3.GetObjectOutput, error) {
+	key, err := s.prepareKey(OpGet, key)
 	if err != nil {
-		return fmt.Errorf("cannot create file: %w", err)
+		return nil, err
 	}
-	defer fd.Close()
 
-	downloader := manager.NewDownloader(s.client)
-	_, err = downloader.Download(context.TODO(), fd,
-		&s3.GetObjectInput{
+	return hedged(s.requestContext(), s.config.Hedge, func(ctx context.Context) (*s3.GetObjectOutput, error) {
+		return s.client.GetObject(ctx, &s3.GetObjectInput{
 			Bucket: aws.String(bucket),
 			Key:    aws.String(key),
 		})
-	return err
+	}, func(output *s3.GetObjectOutput) {
+		output.Body.Close()
+	}, func(output *s3.GetObjectOutput, cancel context.CancelFunc) {
+		output.Body = &cancelBody{ReadCloser: output.Body, cancel: cancel}
+	})
 }
 
-func (s *Storage) PresignGet(bucket, key string, ttl time.Duration) (string, error) {
-	res, err := s.presignClient.PresignGetObject(context.Background(), &s3.GetObjectInput{
+// getRange 는 offset 부터 length byte 를 요청한다. length 가 0 이하이면 끝까지.
+func (s *Storage) getRange(bucket, key string, offset, length int64) (*s3.GetObjectOutput, error) {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.GetObject(s.requestContext(), &s3.GetObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
+		Range:  aws.String(byteRange(offset, length)),
+	})
+}
+
+// byteRange Range 헤더 값
+func byteRange(offset, length int64) string {
+	if length <= 0 {
+		return fmt.Sprintf("bytes=%d-", offset)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+func (s *Storage) getBytes(bucket, key string) ([]byte, error) {
+	output, err := s.getObject(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return io.ReadAll(output.Body)
+}
+
+// Download 는 객체를 targetPath 로 받는다. ctx 에 deadline 이 있으면 검증까지 포함한 단계별 시간을 *DeadlineBudgetError 로 보고한다.
+func (s *Storage) Download(bucket, key, targetPath string, options ...DownloadOption) error {
+	s, b := s.withBudget()
+	return b.finish("Download", s.download(bucket, key, targetPath, options...))
+}
+
+func (s *Storage) download(bucket, key, targetPath string, options ...DownloadOption) error {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return err
+	}
+
+	opt := newDownloadOptions(options)
+
+	// 병렬 range 다운로드라 응답 하나로는 전체 크기와 checksum 을 알 수 없음
+	var (
+		total   = int64(-1)
+		ifMatch *string
+	)
+	if opt.stored || opt.preallocate {
+		head, err := s.client.HeadObject(s.requestContext(), &s3.HeadObjectInput{
+			Bucket:       aws.String(bucket),
+			Key:          aws.String(key),
+			ChecksumMode: types.ChecksumModeEnabled,
+		})
+		if err != nil {
+			return err
+		}
+		if opt.stored {
+			opt.expectStored(head.ETag, head.ContentLength, head.ChecksumSHA256)
+		}
+		total = aws.ToInt64(head.ContentLength)
+		// 받는 도중 객체가 바뀌면 실패
+		ifMatch = head.ETag
+	} else if opt.progress != nil {
+		if info, err := s.Info(bucket, key); err == nil {
+			total = aws.ToInt64(info.ContentLength)
+		}
+	}
+
+	fd, err := os.Create(targetPath)
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer fd.Close()
+
+	var w io.WriterAt = fd
+	if opt.progress != nil {
+		w = &progressWriterAt{w: fd, p: &progress{total: total, fn: opt.progress}}
+	}
+
+	if opt.preallocate {
+		err = preallocate(fd, total)
+	}
+	if err == nil {
+		_, err = s.downloader.Download(s.requestContext(), w,
+			&s3.GetObjectInput{
+				Bucket:  aws.String(bucket),
+				Key:     aws.String(key),
+				IfMatch: ifMatch,
+			}, opt.downloaderOptions)
+	}
+	if err == nil {
+		start := time.Now()
+		err = opt.verifyFile(key, targetPath)
+		budgetFrom(s.requestContext()).addVerify(time.Since(start))
+	}
+	if err == nil && opt.fsync {
+		err = fd.Sync()
+	}
+	if err != nil {
+		// 일부만 받았거나 검증에 실패한 파일은 남기지 않음
+		fd.Close()
+		os.Remove(targetPath)
+		return err
+	}
+
+	return nil
+}
+
+// PresignGet 은 ttl 동안 유효한 GET URL 을 발급한다. ttl 은 Config.PresignTTL 범위로 검사(또는 조정)한다.
+func (s *Storage) PresignGet(bucket, key string, ttl time.Duration, options ...PresignOption) (string, error) {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return "", err
+	}
+
+	ttl, err = s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return "", err
+	}
+
+	opt := newPresignOptions(options)
+	res, err := s.presignClient.PresignGetObject(s.requestContext(), &s3.GetObjectInput{
+		Bucket:                     aws.String(bucket),
+		Key:                        aws.String(key),
+		ResponseContentType:        optionalString(opt.responseContentType),
+		ResponseContentDisposition: optionalString(opt.responseContentDisposition),
 	}, s3.WithPresignExpires(ttl))
 	if err != nil {
 		return "", err
@@ -242,8 +763,19 @@ func (s *Storage) PresignGet(bucket, key string, ttl time.Duration) (string, err
 	return res.URL, nil
 }
 
-func (s *Storage) PresignPut(bucket, key string, ttl time.Duration) (string, error) {
-	res, err := s.presignClient.PresignPutObject(context.Background(), &s3.PutObjectInput{
+// PresignHead 는 ttl 동안 유효한 HEAD URL 을 발급한다. presigned URL 은 method 까지 서명하므로 GET URL 로는 HEAD 를 보낼 수 없다.
+func (s *Storage) PresignHead(bucket, key string, ttl time.Duration) (string, error) {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return "", err
+	}
+
+	ttl, err = s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.presignClient.PresignHeadObject(s.requestContext(), &s3.HeadObjectInput{
 		Bucket: aws.String(bucket),
 		Key:    aws.String(key),
 	}, s3.WithPresignExpires(ttl))
@@ -252,3 +784,27 @@ func (s *Storage) PresignPut(bucket, key string, ttl time.Duration) (string, err
 	}
 	return res.URL, nil
 }
+
+// PresignPut 은 ttl 동안 유효한 PUT URL 을 발급한다. ttl 은 PresignGet 과 같이 검사한다.
+func (s *Storage) PresignPut(bucket, key string, ttl time.Duration, options ...PresignOption) (string, error) {
+	key, err := s.prepareKey(OpPut, key)
+	if err != nil {
+		return "", err
+	}
+
+	ttl, err = s.config.PresignTTL.apply(ttl)
+	if err != nil {
+		return "", err
+	}
+
+	opt := newPresignOptions(options)
+	res, err := s.presignClient.PresignPutObject(s.requestContext(), &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: optionalString(opt.contentType),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return res.URL, nil
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SyncOptions 는 Sync 설정
+type SyncOptions struct {
+	Concurrency int            // 동시 업로드 수, 기본 8
+	Delete      bool           // 로컬에 없는 원격 객체 삭제 (rsync --delete)
+	DryRun      bool           // 비교만 하고 실제로 업로드/삭제하지 않음
+	Upload      []UploadOption // 모든 업로드에 적용
+}
+
+// SyncAction 은 Sync 가 수행한(DryRun 이면 수행할) 작업 하나
+type SyncAction struct {
+	Op     Operation // OpPut, OpDelete
+	Key    string
+	Path   string // 업로드할 로컬 파일
+	Reason string // "new", "size", "etag", "mtime", "orphan"
+	Err    error
+}
+
+// SyncReport 는 Sync 결과
+type SyncReport struct {
+	Actions   []SyncAction
+	Unchanged int
+	DryRun    bool
+}
+
+// Err 는 실패한 작업의 오류를 모두 묶어 반환한다. 모두 성공이면 nil.
+func (r *SyncReport) Err() error {
+	var errs []error
+	for _, action := range r.Actions {
+		if action.Err != nil {
+			errs = append(errs, action.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync 는 localDir 을 bucket 의 prefix 아래로 맞춘다 (로컬 → 원격 단방향 미러링). 새 파일과 바뀐 파일을 업로드하고,
+// Delete 이면 로컬에 없는 원격 객체를 삭제한다. key 는 UploadDir 과 같이 prefix + 상대 경로.
+// 원격에서 바뀐 객체를 내려받거나 양쪽 변경의 충돌을 처리하지 않으며, 다르면 항상 로컬 파일이 이긴다.
+// 크기가 다르면 바뀐 것으로 보고, 같으면 로컬 수정 시각이 객체보다 최근일 때만 내용을 확인한다.
+// ETag 가 MD5 이면 로컬 파일의 MD5 와 비교하고, 아니면(multipart) 수정 시각만으로 판단한다.
+// 개별 작업의 실패는 SyncAction.Err 에 담기며, 목록 조회에 실패하면 아무것도 바꾸지 않고 오류를 반환한다.
+// 이 패키지의 관리용 객체(통계, 삭제 목록, canary, 선점 마커, 블록 인덱스)는 비교와 삭제에서 제외한다.
+// Config.GuardedDelete 이면 orphan 을 바로 삭제하지 않고 삭제 목록을 저장한 뒤 report 와 함께 *ConfirmError 를 반환한다.
+// 작업마다 Config.Blackouts 시간대가 끝나기를 기다린다.
+func (s *Storage) Sync(bucket, prefix, localDir string, opt SyncOptions) (*SyncReport, error) {
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 8
+	}
+
+	files, err := dirFiles(localDir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	files = slices.DeleteFunc(files, func(file UploadResult) bool {
+		return internalKey(file.Key)
+	})
+
+	remote := map[string]ObjectInfo{}
+	err = s.Walk(bucket, prefix, func(obj ObjectInfo) error {
+		remote[obj.Key] = obj
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for key := range remote {
+		if syncSidecar(prefix, key) {
+			delete(remote, key)
+		}
+	}
+
+	report := &SyncReport{DryRun: opt.DryRun}
+	for _, file := range files {
+		obj, found := remote[file.Key]
+		delete(remote, file.Key)
+
+		reason, err := syncReason(file.Path, file.Size, obj, found)
+		switch {
+		case err != nil:
+			report.Actions = append(report.Actions, SyncAction{Op: OpPut, Key: file.Key, Path: file.Path, Err: err})
+		case reason == "":
+			report.Unchanged++
+		default:
+			report.Actions = append(report.Actions, SyncAction{Op: OpPut, Key: file.Key, Path: file.Path, Reason: reason})
+		}
+	}
+
+	var orphans []string
+	if opt.Delete {
+		for key := range remote {
+			orphans = append(orphans, key)
+		}
+		slices.Sort(orphans)
+		for _, key := range orphans {
+			report.Actions = append(report.Actions, SyncAction{Op: OpDelete, Key: key, Reason: "orphan"})
+		}
+	}
+
+	if opt.DryRun {
+		return report, nil
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, opt.Concurrency)
+	)
+	for i := range report.Actions {
+		action := &report.Actions[i]
+		if action.Op != OpPut || action.Err != nil {
+			continue
+		}
+		if _, err = s.WaitBlackout(); err != nil {
+			action.Err = err
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			action.Err = s.Upload(bucket, action.Key, action.Path, opt.Upload...)
+		}()
+	}
+	wg.Wait()
+
+	if len(orphans) == 0 {
+		return report, nil
+	}
+	if s.config.GuardedDelete {
+		return report, s.saveDeletePlan(bucket, prefix, orphans)
+	}
+
+	failed := map[string]error{}
+	if _, err = s.WaitBlackout(); err == nil {
+		failed, err = s.DeleteMany(bucket, orphans)
+	}
+	for i := range report.Actions {
+		action := &report.Actions[i]
+		if action.Op != OpDelete {
+			continue
+		}
+		if err != nil {
+			action.Err = err
+		} else {
+			action.Err = failed[action.Key]
+		}
+	}
+
+	return report, nil
+}
+
+// syncSidecar 는 목록에는 나오지만 Sync 가 건드리지 않는 관리용 객체인지 확인한다. Claim 마커(processing/<key>)
+func syncSidecar(prefix, key string) bool {
+	return strings.HasPrefix(key, claimPrefix) && !strings.HasPrefix(prefix, claimPrefix)
+}
+
+// syncReason 은 로컬 파일을 업로드해야 하는 이유, 같으면 "".
+func syncReason(path string, size int64, obj ObjectInfo, found bool) (string, error) {
+	if !found {
+		return "new", nil
+	}
+	if size != obj.Size {
+		return "size", nil
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !stat.ModTime().After(obj.LastModified) {
+		return "", nil
+	}
+
+	// 수정 시각만 바뀐(touch) 파일은 내용이 같으면 건너뜀
+	if !isMD5(obj.ETag) {
+		return "mtime", nil
+	}
+	sum, err := fileMD5(path)
+	if err != nil {
+		return "", err
+	}
+	if sum != obj.ETag {
+		return "etag", nil
+	}
+	return "", nil
+}
+
+func fileMD5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := md5.New()
+	if _, err = io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
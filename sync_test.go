@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSyncReason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("abc"), 0o644)
+	stat, _ := os.Stat(path)
+	older, newer := stat.ModTime().Add(-time.Hour), stat.ModTime().Add(time.Hour)
+
+	const md5abc = "900150983cd24fb0d6963f7d28e17f72"
+	cases := []struct {
+		obj   ObjectInfo
+		found bool
+		want  string
+	}{
+		{ObjectInfo{}, false, "new"},
+		{ObjectInfo{Size: 4, LastModified: newer}, true, "size"},
+		{ObjectInfo{Size: 3, LastModified: newer}, true, ""},               // 원격이 더 최근
+		{ObjectInfo{Size: 3, LastModified: older, ETag: md5abc}, true, ""}, // touch 만 됨
+		{ObjectInfo{Size: 3, LastModified: older, ETag: md5abc[1:] + "0"}, true, "etag"},
+		{ObjectInfo{Size: 3, LastModified: older, ETag: md5abc + "-2"}, true, "mtime"}, // multipart
+	}
+	for i, c := range cases {
+		got, err := syncReason(path, 3, c.obj, c.found)
+		if err != nil || got != c.want {
+			t.Errorf("%d: %q %v", i, got, err)
+		}
+	}
+}
+
+func TestSyncReportErr(t *testing.T) {
+	report := &SyncReport{Actions: []SyncAction{{Key: "a"}, {Key: "b"}}}
+	if report.Err() != nil {
+		t.Error(report.Err())
+	}
+
+	report.Actions[1].Err = ErrNotFound
+	if report.Err() == nil {
+		t.Error("실패가 누락됨")
+	}
+}
+
+func TestSyncDelete(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abc"), 0o644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+	os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0o644)
+
+	sidecars := []string{dirStatsName, "sub/" + dirStatsName, deleteManifestPrefix + "p.json", canaryPrefix + "x", claimPrefix + "a.txt", "a.txt" + blockIndexSuffix}
+
+	setup := func(config Config) (*Storage, *fakeS3) {
+		store, fake := newFakeStorage(t, config)
+		later := time.Now().Add(time.Hour)
+		fake.put("bucket", "a.txt", []byte("abc"), later)
+		fake.put("bucket", "old.txt", []byte("old"))
+		fake.put("bucket", "orphan"+blockIndexSuffix, []byte("{}")) // 원본이 없어도 인덱스는 건드리지 않음
+		for _, key := range sidecars {
+			fake.put("bucket", key, []byte("{}"))
+		}
+		return store, fake
+	}
+
+	store, fake := setup(Config{})
+	report, err := store.Sync("bucket", "", dir, SyncOptions{Delete: true})
+	if err != nil || report.Err() != nil {
+		t.Fatalf("sync: %v, %v", err, report.Err())
+	}
+
+	var orphans, uploads []string
+	for _, action := range report.Actions {
+		switch action.Op {
+		case OpDelete:
+			orphans = append(orphans, action.Key)
+		case OpPut:
+			uploads = append(uploads, action.Key)
+		}
+	}
+	if !slices.Equal(orphans, []string{"old.txt"}) {
+		t.Fatalf("orphans %v", orphans)
+	}
+	if !slices.Equal(uploads, []string{"sub/b.txt"}) || report.Unchanged != 1 {
+		t.Fatalf("uploads %v, unchanged %d", uploads, report.Unchanged)
+	}
+
+	want := append([]string{"a.txt", "orphan" + blockIndexSuffix, "sub/b.txt"}, sidecars...)
+	slices.Sort(want)
+	if got := fake.keys("bucket"); !slices.Equal(got, want) {
+		t.Fatalf("remote %v, want %v", got, want)
+	}
+
+	// GuardedDelete 이면 확인 전에는 삭제하지 않음
+	store, fake = setup(Config{GuardedDelete: true})
+	_, err = store.Sync("bucket", "", dir, SyncOptions{Delete: true})
+	var confirm *ConfirmError
+	if !errors.As(err, &confirm) || confirm.Count != 1 {
+		t.Fatalf("expected ConfirmError for 1 object, got %v", err)
+	}
+	if fake.get("bucket", "old.txt") == nil {
+		t.Fatal("deleted before confirm")
+	}
+	if _, err = store.Confirm(confirm.ManifestID); err != nil || fake.get("bucket", "old.txt") != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+}
+
+func TestSyncEmptyFile(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "empty.txt"), nil, 0o644)
+
+	report, err := store.Sync("bucket", "", dir, SyncOptions{})
+	if err != nil || report.Err() != nil {
+		t.Fatalf("sync: %v, %v", err, report.Err())
+	}
+	if data := fake.get("bucket", "empty.txt"); data == nil || len(data) != 0 {
+		t.Fatalf("empty.txt = %q", data)
+	}
+
+	// 두 번째 실행에서는 같은 파일로 본다
+	report, err = store.Sync("bucket", "", dir, SyncOptions{})
+	if err != nil || len(report.Actions) != 0 || report.Unchanged != 1 {
+		t.Fatalf("second sync: %+v, %v", report, err)
+	}
+}
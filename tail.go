@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// TailOptions 는 Tail 설정
+type TailOptions struct {
+	Interval time.Duration // 확인 주기, 기본 5s
+	Offset   int64         // 시작 위치, -1 이면 현재 끝부터 (이후 추가된 내용만)
+}
+
+// Tail 은 ctx 가 끝날 때까지 Interval 마다 객체를 확인해 마지막으로 읽은 위치 이후의 byte 를 fn 으로 전달한다.
+// 다른 시스템이 계속 덧붙여 다시 올리는 로그 객체를 따라갈 때 사용한다.
+// 객체가 아직 없으면 생길 때까지 기다리고, 읽은 위치보다 작아지면(다른 내용으로 교체) 처음부터 다시 읽는다.
+// 확인과 읽기 사이에 객체가 바뀌면 그 회차는 건너뛴다. fn 이 오류를 반환하면 멈추고 그 오류를 반환한다.
+func (s *Storage) Tail(ctx context.Context, bucket, key string, fn func(data []byte) error, options ...TailOptions) error {
+	key, err := s.prepareKey(OpGet, key)
+	if err != nil {
+		return err
+	}
+
+	var opt TailOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(opt.Interval)
+	defer ticker.Stop()
+
+	offset := opt.Offset
+	for {
+		if offset, err = s.tail(ctx, bucket, key, offset, fn); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// tail 은 한 회차 확인, 새 offset 을 반환한다.
+func (s *Storage) tail(ctx context.Context, bucket, key string, offset int64, fn func([]byte) error) (int64, error) {
+	head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if isNotFound(err) {
+		// 없던 객체가 생기면 전체가 새 내용
+		return max(offset, 0), nil
+	}
+	if err != nil {
+		return offset, err
+	}
+
+	size := aws.ToInt64(head.ContentLength)
+	offset = tailOffset(offset, size)
+	if offset >= size {
+		return offset, nil
+	}
+
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Range:   aws.String(byteRange(offset, size-offset)),
+		IfMatch: head.ETag,
+	})
+	if err != nil {
+		switch errorCode(err) {
+		case "PreconditionFailed", "InvalidRange":
+			return offset, nil
+		}
+		if isNotFound(err) {
+			return offset, nil
+		}
+		return offset, err
+	}
+	defer output.Body.Close()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := output.Body.Read(buf)
+		if n > 0 {
+			if err := fn(buf[:n]); err != nil {
+				return offset, err
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			// 받은 만큼은 전달했으므로 다음 회차에 이어서 읽음
+			return offset, nil
+		}
+	}
+}
+
+// tailOffset 은 객체 크기에 맞춰 읽기 시작 위치를 정한다.
+func tailOffset(offset, size int64) int64 {
+	switch {
+	case offset < 0:
+		return size
+	case offset > size:
+		return 0
+	default:
+		return offset
+	}
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "testing"
+
+func TestTailOffset(t *testing.T) {
+	cases := []struct{ offset, size, want int64 }{
+		{0, 100, 0},
+		{40, 100, 40},
+		{100, 100, 100},
+		{-1, 100, 100}, // 현재 끝부터
+		{150, 100, 0},  // 교체되어 작아짐
+	}
+	for _, c := range cases {
+		if got := tailOffset(c.offset, c.size); got != c.want {
+			t.Errorf("tailOffset(%d, %d) = %d", c.offset, c.size, got)
+		}
+	}
+}
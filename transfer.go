@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrTransferCanceled = errors.New("transfer canceled")
+
+type Priority int
+
+const (
+	Batch       Priority = iota // 백그라운드 동기화 등
+	Interactive                 // 사용자 요청, 대기 중인 Batch 보다 먼저 실행
+)
+
+// Transfer 는 TransferManager 에 등록된 작업
+type Transfer struct {
+	done chan struct{}
+	err  error
+}
+
+// Wait 는 작업이 끝날 때까지 기다린 뒤 결과를 반환한다.
+func (t *Transfer) Wait() error {
+	<-t.done
+	return t.err
+}
+
+type transferJob struct {
+	transfer *Transfer
+	run      func() error
+}
+
+// TransferManager 는 고정된 수의 worker 로 전송 작업을 실행한다.
+// 대기 중인 작업은 항상 Interactive 가 Batch 보다 먼저 실행되며,
+// 이미 실행 중인 작업은 중단하지 않는다.
+// Config.Blackouts 시간대에는 Batch 작업을 시작하지 않는다.
+type TransferManager struct {
+	storage *Storage
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues [2][]transferJob // Priority 별 대기열
+	closed bool
+	wg     sync.WaitGroup
+	wakeup *time.Timer // blackout 이 끝나면 대기 중인 worker 를 깨움
+}
+
+func (s *Storage) TransferManager(workers int) *TransferManager {
+	if workers < 1 {
+		workers = 1
+	}
+
+	m := &TransferManager{storage: s}
+	m.cond = sync.NewCond(&m.mu)
+
+	m.wg.Add(workers)
+	for range workers {
+		go m.worker()
+	}
+
+	return m
+}
+
+func (m *TransferManager) Upload(priority Priority, bucket, key, origin string, options ...UploadOption) *Transfer {
+	return m.Submit(priority, func() error {
+		return m.storage.Upload(bucket, key, origin, options...)
+	})
+}
+
+func (m *TransferManager) Download(priority Priority, bucket, key, targetPath string, options ...DownloadOption) *Transfer {
+	return m.Submit(priority, func() error {
+		return m.storage.Download(bucket, key, targetPath, options...)
+	})
+}
+
+// Submit 은 임의의 작업을 대기열에 넣는다.
+func (m *TransferManager) Submit(priority Priority, fn func() error) *Transfer {
+	transfer := &Transfer{done: make(chan struct{})}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		transfer.err = ErrTransferCanceled
+		close(transfer.done)
+		return transfer
+	}
+
+	priority = normalizePriority(priority)
+	m.queues[priority] = append(m.queues[priority], transferJob{transfer: transfer, run: fn})
+	m.cond.Signal()
+	return transfer
+}
+
+// Pending 은 아직 시작하지 않은 작업 수. Submit 과 같이 Interactive 가 아닌 값은 Batch 로 본다.
+func (m *TransferManager) Pending(priority Priority) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.queues[normalizePriority(priority)])
+}
+
+// normalizePriority Interactive 외에는 Batch
+func normalizePriority(priority Priority) Priority {
+	if priority != Interactive {
+		return Batch
+	}
+	return priority
+}
+
+// Close 는 대기 중인 작업을 ErrTransferCanceled 로 끝내고 실행 중인 작업이 끝날 때까지 기다린다.
+func (m *TransferManager) Close() {
+	m.mu.Lock()
+	m.closed = true
+	for i, queue := range m.queues {
+		for _, job := range queue {
+			job.transfer.err = ErrTransferCanceled
+			close(job.transfer.done)
+		}
+		m.queues[i] = nil
+	}
+	m.cond.Broadcast()
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+func (m *TransferManager) worker() {
+	defer m.wg.Done()
+
+	for {
+		job, ok := m.next()
+		if !ok {
+			return
+		}
+
+		job.transfer.err = job.run()
+		close(job.transfer.done)
+	}
+}
+
+func (m *TransferManager) next() (transferJob, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		if m.closed {
+			return transferJob{}, false
+		}
+
+		if queue := m.queues[Interactive]; len(queue) > 0 {
+			m.queues[Interactive] = queue[1:]
+			return queue[0], true
+		}
+
+		if queue := m.queues[Batch]; len(queue) > 0 {
+			remaining := m.storage.blackoutRemaining()
+			if remaining == 0 {
+				m.queues[Batch] = queue[1:]
+				return queue[0], true
+			}
+			if m.wakeup == nil {
+				m.wakeup = time.AfterFunc(remaining, func() {
+					m.mu.Lock()
+					m.wakeup = nil
+					m.cond.Broadcast()
+					m.mu.Unlock()
+				})
+			}
+		}
+
+		m.cond.Wait()
+	}
+}
@@ -0,0 +1,96 @@
+package storage_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pro200/go-storage"
+)
+
+func TestTransferManagerPriority(t *testing.T) {
+	store, err := storage.New(storage.Config{Endpoint: "s3.us-west-004.backblazeb2.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := store.TransferManager(1)
+
+	// worker 하나를 붙잡아 두고 대기열을 쌓는다
+	block := make(chan struct{})
+	first := manager.Submit(storage.Batch, func() error {
+		<-block
+		return nil
+	})
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) func() error {
+		return func() error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	batch := manager.Submit(storage.Batch, record("batch"))
+	interactive := manager.Submit(storage.Interactive, record("interactive"))
+	close(block)
+
+	for _, transfer := range []*storage.Transfer{first, batch, interactive} {
+		if err := transfer.Wait(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(order) != 2 || order[0] != "interactive" {
+		t.Error("Interactive 작업이 먼저 실행되지 않음:", order)
+	}
+
+	manager.Close()
+	if err := manager.Submit(storage.Batch, record("late")).Wait(); !errors.Is(err, storage.ErrTransferCanceled) {
+		t.Error("Close 이후 작업이 취소되지 않음:", err)
+	}
+}
+
+func TestTransferManagerPending(t *testing.T) {
+	store, err := storage.New(storage.Config{Endpoint: "s3.us-west-004.backblazeb2.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := store.TransferManager(1)
+	defer manager.Close()
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	first := manager.Submit(storage.Batch, func() error {
+		close(started)
+		<-block
+		return nil
+	})
+	<-started
+
+	noop := func() error { return nil }
+	manager.Submit(storage.Interactive, noop)
+	manager.Submit(storage.Priority(7), noop) // 알 수 없는 값은 Batch
+	manager.Submit(storage.Batch, noop)
+
+	if n := manager.Pending(storage.Interactive); n != 1 {
+		t.Errorf("Interactive pending %d", n)
+	}
+	if n := manager.Pending(storage.Batch); n != 2 {
+		t.Errorf("Batch pending %d", n)
+	}
+	for _, priority := range []storage.Priority{-1, 2, 7} {
+		if n := manager.Pending(priority); n != 2 {
+			t.Errorf("Pending(%d) = %d", priority, n)
+		}
+	}
+
+	close(block)
+	first.Wait()
+}
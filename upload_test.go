@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUploadReader(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	// 길이를 알 수 없는 pipe
+	reader, writer := io.Pipe()
+	go func() {
+		io.WriteString(writer, "hello ")
+		io.WriteString(writer, "stream")
+		writer.Close()
+	}()
+	if err := store.UploadReader("bucket", "logs/out.txt", reader, WithChecksum(ChecksumMD5)); err != nil {
+		t.Fatal(err)
+	}
+	object := fake.objects["bucket/logs/out.txt"]
+	if string(object.data) != "hello stream" || object.headers.Get("Content-Type") != "text/plain" {
+		t.Errorf("object %q, %v", object.data, object.headers)
+	}
+
+	if err := store.UploadReader("bucket", "empty.txt", strings.NewReader("")); err == nil {
+		t.Error("빈 스트림이 허용됨")
+	}
+	if err := store.UploadReader("bucket", "", strings.NewReader("x")); err == nil {
+		t.Error("빈 key 가 허용됨")
+	}
+}
+
+func TestUploadReaderMultipart(t *testing.T) {
+	store, fake := newFakeStorage(t, Config{})
+
+	var parts atomic.Int64
+	fake.fail = func(r *http.Request) int {
+		if r.Method == http.MethodPut && r.URL.Query().Has("partNumber") {
+			parts.Add(1)
+		}
+		return 0
+	}
+
+	data := bytes.Repeat([]byte("0123456789"), (minPartSize+minPartSize/2)/10)
+	var transferred atomic.Int64
+	err := store.UploadReader("bucket", "big.bin", io.MultiReader(bytes.NewReader(data)),
+		WithPartSize(minPartSize), WithProgress(func(n, total int64) { transferred.Store(n) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fake.get("bucket", "big.bin"), data) || parts.Load() != 2 {
+		t.Errorf("size %d, parts %d", len(fake.get("bucket", "big.bin")), parts.Load())
+	}
+	if transferred.Load() != int64(len(data)) {
+		t.Errorf("progress %d / %d", transferred.Load(), len(data))
+	}
+
+	// 읽기 오류는 그대로 전달
+	errRead := errors.New("broken pipe")
+	reader, writer := io.Pipe()
+	go func() {
+		writer.Write([]byte("partial"))
+		writer.CloseWithError(errRead)
+	}()
+	if err := store.UploadReader("bucket", "broken.bin", reader); !errors.Is(err, errRead) {
+		t.Errorf("read error %v", err)
+	}
+	if fake.get("bucket", "broken.bin") != nil {
+		t.Error("실패한 업로드가 저장됨")
+	}
+}